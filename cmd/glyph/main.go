@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -15,6 +16,9 @@ func main() {
 		log.Fatal(err)
 	}
 	resolver := core.NewWorkspaceResolver(cwd)
+	if len(os.Args) > 1 {
+		os.Exit(shell.RunCLI(os.Args[1:], resolver, os.Stdout, os.Stderr))
+	}
 	state := &core.State{}
 	model := shell.NewModel(state, resolver)
 
@@ -22,4 +26,8 @@ func main() {
 	if err := program.Start(); err != nil {
 		log.Fatal(err)
 	}
+	if err := model.ExitErr(); err != nil {
+		fmt.Fprintln(os.Stderr, "glyph:", err)
+		os.Exit(1)
+	}
 }
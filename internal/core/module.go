@@ -13,7 +13,48 @@ type Module interface {
 	Hint() string
 }
 
-// CoreContext carries shared runtime context for modules.
+// CommandProvider is implemented by modules that add actions to the
+// palette.
+type CommandProvider interface {
+	// Commands lists the module's actions. IDs are local to the module and
+	// Args hold the arguments each one runs with.
+	Commands() []Command
+	// RunCommand runs the action id. The module has been initialized.
+	RunCommand(ctx CoreContext, id string, args map[string]string) (Module, tea.Cmd)
+}
+
+// ActivateMsg is sent to a module when its app becomes the active tab.
+type ActivateMsg struct{}
+
+// DeactivateMsg is sent when another tab takes focus or the app closes.
+type DeactivateMsg struct{}
+
+// WorkspaceChangedMsg is sent when the workspace behind a module's context
+// changes, such as when a project .glyph folder is created.
+type WorkspaceChangedMsg struct {
+	Workspace Workspace
+}
+
+// ShutdownMsg is sent before Glyph exits so modules can save. Commands
+// returned for it are not run; a module whose save fails reports it through
+// ShutdownReporter.
+type ShutdownMsg struct{}
+
+// ShutdownReporter is implemented by modules whose shutdown can fail. Glyph
+// asks after sending ShutdownMsg and prints the error once it has exited.
+type ShutdownReporter interface {
+	ShutdownErr() error
+}
+
+// CoreContext carries shared runtime context for modules. The shell builds
+// one per module; the zero value works but persists nothing.
 type CoreContext struct {
+	// RootPath is the .glyph folder of the workspace.
 	RootPath string
+	// ModuleID is the module the context was built for.
+	ModuleID  string
+	Workspace Workspace
+	// Store keeps the module's data in the workspace.
+	Store Store
+	Theme Theme
 }
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// NotifyMsg asks the shell to show Text in the hint bar.
+type NotifyMsg struct {
+	Text string
+}
+
+// ExecMsg asks the shell to run Command with its output captured. The
+// shell answers the module with an ExecResultMsg carrying the same ID.
+type ExecMsg struct {
+	ModuleID string
+	ID       string
+	Command  Command
+}
+
+// ExecResultMsg reports a finished ExecMsg. Output holds stdout and stderr
+// as they were written.
+type ExecResultMsg struct {
+	ID     string
+	Output string
+	Err    error
+}
+
+// LaunchMsg asks the shell to run a palette command as if it were picked.
+type LaunchMsg struct {
+	CommandID string
+}
+
+// Notify shows text in the hint bar for a few seconds.
+func (c CoreContext) Notify(text string) tea.Cmd {
+	return func() tea.Msg {
+		return NotifyMsg{Text: text}
+	}
+}
+
+// Exec runs command through the shell's executor without taking over the
+// terminal. Run or Argv, Shell, Dir, Env and Timeout apply as for config
+// commands; the result comes back to the module as an ExecResultMsg.
+func (c CoreContext) Exec(id string, command Command) tea.Cmd {
+	moduleID := c.ModuleID
+	return func() tea.Msg {
+		return ExecMsg{ModuleID: moduleID, ID: id, Command: command}
+	}
+}
+
+// Launch runs the palette command commandID, confirmation included.
+func (c CoreContext) Launch(commandID string) tea.Cmd {
+	return func() tea.Msg {
+		return LaunchMsg{CommandID: commandID}
+	}
+}
+
+// Store is a module's key-value store. Each key is a file in the module's
+// folder under the workspace root, so values are easy to inspect.
+type Store struct {
+	dir string
+}
+
+// NewStore returns the store of moduleID in the workspace rootPath.
+func NewStore(rootPath, moduleID string) Store {
+	if strings.TrimSpace(rootPath) == "" || strings.TrimSpace(moduleID) == "" {
+		return Store{}
+	}
+	return Store{dir: filepath.Join(rootPath, moduleID)}
+}
+
+// Dir is the folder holding the values; empty when nothing is persisted.
+func (s Store) Dir() string {
+	return s.dir
+}
+
+// Get returns the value of key; ok is false when it was never set.
+func (s Store) Get(key string) (value []byte, ok bool, err error) {
+	path, err := s.path(key)
+	if err != nil || path == "" {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set stores value under key.
+func (s Store) Set(key string, value []byte) error {
+	path, err := s.path(key)
+	if err != nil || path == "" {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, value, 0o644)
+}
+
+// Delete removes key; a missing key is not an error.
+func (s Store) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil || path == "" {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// GetJSON decodes the value of key into v.
+func (s Store) GetJSON(key string, v any) (bool, error) {
+	data, ok, err := s.Get(key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetJSON stores v under key as indented JSON.
+func (s Store) SetJSON(key string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.Set(key, append(data, '\n'))
+}
+
+func (s Store) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key == "." || key == ".." {
+		return "", errors.New("invalid store key: " + key)
+	}
+	if s.dir == "" {
+		return "", nil
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// Theme is the palette Glyph draws with, as lipgloss color strings.
+type Theme struct {
+	Accent    string `json:"accent"`
+	Text      string `json:"text"`
+	Muted     string `json:"muted"`
+	Subtle    string `json:"subtle"`
+	Highlight string `json:"highlight"`
+	// OnHighlight is text drawn over Highlight.
+	OnHighlight string `json:"onHighlight"`
+	Error       string `json:"error"`
+	Warning     string `json:"warning"`
+}
+
+// DefaultTheme is Glyph's built-in palette.
+func DefaultTheme() Theme {
+	return Theme{
+		Accent:      "#FF9F68",
+		Text:        "#E7EBF2",
+		Muted:       "#8A90A6",
+		Subtle:      "#5C6475",
+		Highlight:   "#FFD9A0",
+		OnHighlight: "#2F1E0C",
+		Error:       "#FF6B6B",
+		Warning:     "#FFB86C",
+	}
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 // CommandKind describes the command type used by the launcher.
 type CommandKind string
 
@@ -7,8 +9,65 @@ const (
 	CommandApp    CommandKind = "app"
 	CommandAction CommandKind = "action"
 	CommandExec   CommandKind = "exec"
+	CommandChain  CommandKind = "chain"
+)
+
+// PausePolicy controls when Glyph waits for Enter after a command exits.
+type PausePolicy string
+
+const (
+	PauseAuto      PausePolicy = "auto"
+	PauseAlways    PausePolicy = "always"
+	PauseNever     PausePolicy = "never"
+	PauseOnFailure PausePolicy = "failure"
+)
+
+// ReturnTarget is where Glyph goes after a command exits.
+type ReturnTarget string
+
+const (
+	ReturnPalette ReturnTarget = "palette"
+	ReturnMain    ReturnTarget = "main"
+	ReturnQuit    ReturnTarget = "quit"
+)
+
+// RunMode selects how a command's process is attached to Glyph.
+type RunMode string
+
+const (
+	RunTerminal   RunMode = ""
+	RunBackground RunMode = "background"
+	RunCapture    RunMode = "capture"
+)
+
+// ConfirmKind is the confirmation required before a command runs.
+type ConfirmKind string
+
+const (
+	ConfirmNone  ConfirmKind = ""
+	ConfirmYesNo ConfirmKind = "yes"
+	// ConfirmTypeName requires typing the command ID.
+	ConfirmTypeName ConfirmKind = "type"
+)
+
+// DangerLevel marks how destructive a command is.
+type DangerLevel string
+
+const (
+	DangerNone   DangerLevel = ""
+	DangerLow    DangerLevel = "low"
+	DangerMedium DangerLevel = "medium"
+	DangerHigh   DangerLevel = "high"
 )
 
+// AfterRun describes post-run behavior. The zero value pauses after quick
+// runs, clears the screen and returns to the palette.
+type AfterRun struct {
+	Pause      PausePolicy
+	Return     ReturnTarget
+	KeepScreen bool
+}
+
 // Command describes a runnable command in the palette.
 type Command struct {
 	ID       string
@@ -20,6 +79,50 @@ type Command struct {
 	Source   string
 	Managed  bool
 	ToolID   string
+
+	// Spellbook is the name of the spellbook that provides the command.
+	Spellbook string
+	// Tags are free-form labels matched by "#tag" in the launcher.
+	Tags []string
+	// Description is optional help text shown next to the label.
+	Description string
+	// SourceFile is the config or manifest file that declared the command.
+	SourceFile string
+	// Script is the absolute path of the script file behind Run, if any.
+	Script string
+	// SuggestedShortcuts are keys the command's spellbook proposes; any
+	// binding from config wins over them.
+	SuggestedShortcuts []string
+
+	// Argv, when set, is executed directly instead of Run.
+	Argv []string
+	// Shell selects the interpreter for Run ("" uses the platform default,
+	// "none" executes Run directly without a shell).
+	Shell string
+	// Dir is the absolute working directory; empty means the start folder.
+	Dir string
+	// Env holds per-command overrides, applied after EnvFiles.
+	Env      map[string]string
+	EnvFiles []string
+	After    AfterRun
+	RunMode  RunMode
+	// Timeout stops the run when it is exceeded; zero means no limit.
+	Timeout time.Duration
+	Confirm ConfirmKind
+	Danger  DangerLevel
+
+	// Steps, StopOnError and Args describe a CommandChain.
+	Steps       []ChainStep
+	StopOnError bool
+	Args        map[string]string
+}
+
+// ChainStep is one step of a chain: either a reference to another command
+// ID or an inline command.
+type ChainStep struct {
+	Ref             string
+	Inline          Command
+	ContinueOnError bool
 }
 
 // State holds shared app state across UI.
@@ -0,0 +1,134 @@
+// Package detect recognizes project ecosystems in a directory and turns
+// their build files and task runners into runnable tasks.
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Task is a runnable task discovered in a project.
+type Task struct {
+	// Runner identifies where the task came from: go, npm, make, just, ...
+	Runner string
+	// Name is the runner's own name for the task, e.g. a make target.
+	Name        string
+	Label       string
+	Description string
+	Run         string
+	// Dir is the directory the task must run in.
+	Dir string
+	// File is the build or task file the task was read from.
+	File string
+	// Capture and Background suggest how the task is best run.
+	Capture    bool
+	Background bool
+}
+
+// ComposeFiles lists the file names docker compose picks up by default.
+var ComposeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// TaskfileNames lists the file names the task runner picks up by default.
+var TaskfileNames = []string{"Taskfile.yml", "Taskfile.yaml", "taskfile.yml", "taskfile.yaml"}
+
+// JustfileNames lists the file names just picks up by default.
+var JustfileNames = []string{"justfile", "Justfile", ".justfile"}
+
+// MakefileNames lists the file names make picks up by default.
+var MakefileNames = []string{"GNUmakefile", "makefile", "Makefile"}
+
+// Detect returns the tasks of every ecosystem recognized in dir. Files that
+// cannot be parsed are skipped; detection is best effort.
+func Detect(dir string) []Task {
+	var tasks []Task
+	tasks = append(tasks, goTasks(dir)...)
+	if path := filepath.Join(dir, "package.json"); fileExists(path) {
+		found, _ := PackageScripts(path)
+		tasks = append(tasks, found...)
+	}
+	if path, ok := firstExisting(dir, MakefileNames); ok {
+		found, _ := MakeTargets(path)
+		tasks = append(tasks, found...)
+	}
+	if path, ok := firstExisting(dir, JustfileNames); ok {
+		found, _ := JustRecipes(path)
+		tasks = append(tasks, found...)
+	}
+	if path, ok := firstExisting(dir, TaskfileNames); ok {
+		found, _ := TaskfileTasks(path)
+		tasks = append(tasks, found...)
+	}
+	tasks = append(tasks, composeTasks(dir)...)
+	tasks = append(tasks, cargoTasks(dir)...)
+	return tasks
+}
+
+func goTasks(dir string) []Task {
+	path := filepath.Join(dir, "go.mod")
+	if !fileExists(path) {
+		return nil
+	}
+	return withFile(path, []Task{
+		{Runner: "go", Name: "build", Label: "Go: Build", Run: "go build ./...", Dir: dir, Capture: true},
+		{Runner: "go", Name: "test", Label: "Go: Test", Run: "go test ./...", Dir: dir, Capture: true},
+		{Runner: "go", Name: "vet", Label: "Go: Vet", Run: "go vet ./...", Dir: dir, Capture: true},
+		{Runner: "go", Name: "tidy", Label: "Go: Mod Tidy", Run: "go mod tidy", Dir: dir, Capture: true},
+	})
+}
+
+func composeTasks(dir string) []Task {
+	path, ok := firstExisting(dir, ComposeFiles)
+	if !ok {
+		return nil
+	}
+	return withFile(path, []Task{
+		{Runner: "compose", Name: "up", Label: "Compose: Up", Run: "docker compose up -d", Dir: dir},
+		{Runner: "compose", Name: "down", Label: "Compose: Down", Run: "docker compose down", Dir: dir},
+		{Runner: "compose", Name: "ps", Label: "Compose: Services", Run: "docker compose ps -a", Dir: dir, Capture: true},
+		{Runner: "compose", Name: "logs", Label: "Compose: Follow Logs", Run: "docker compose logs -f", Dir: dir, Background: true},
+	})
+}
+
+func cargoTasks(dir string) []Task {
+	path := filepath.Join(dir, "Cargo.toml")
+	if !fileExists(path) {
+		return nil
+	}
+	return withFile(path, []Task{
+		{Runner: "cargo", Name: "build", Label: "Cargo: Build", Run: "cargo build", Dir: dir, Capture: true},
+		{Runner: "cargo", Name: "test", Label: "Cargo: Test", Run: "cargo test", Dir: dir, Capture: true},
+		{Runner: "cargo", Name: "run", Label: "Cargo: Run", Run: "cargo run", Dir: dir},
+		{Runner: "cargo", Name: "clippy", Label: "Cargo: Clippy", Run: "cargo clippy", Dir: dir, Capture: true},
+	})
+}
+
+func withFile(path string, tasks []Task) []Task {
+	for i := range tasks {
+		tasks[i].File = path
+	}
+	return tasks
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func firstExisting(dir string, names []string) (string, bool) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if fileExists(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// shellWord quotes name for a POSIX shell when it needs it.
+func shellWord(name string) string {
+	if name != "" && !strings.ContainsAny(name, " \t\n'\"\\$`&|;<>()*?[]{}~#!") {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", `'\''`) + "'"
+}
@@ -0,0 +1,77 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var justRecipePattern = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)((?:\s+[^:]*)?)\s*:(?:[^=]|$)`)
+
+// JustRecipes returns the public recipes of a justfile, the same set
+// `just --summary` lists. A comment directly above a recipe becomes its
+// description. Recipes with required parameters are skipped because they
+// cannot run without arguments.
+func JustRecipes(path string) ([]Task, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dir := filepath.Dir(path)
+	var tasks []Task
+	var comment string
+	private := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			comment, private = "", false
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			if strings.Contains(trimmed, "private") {
+				private = true
+			}
+			continue
+		}
+
+		match := justRecipePattern.FindStringSubmatch(line)
+		if match == nil || match[1] == "alias" || match[1] == "set" || match[1] == "export" || match[1] == "import" || match[1] == "mod" {
+			comment, private = "", false
+			continue
+		}
+		name := match[1]
+		if !private && !strings.HasPrefix(name, "_") && !hasRequiredParams(match[2]) {
+			tasks = append(tasks, Task{
+				Runner:      "just",
+				Name:        name,
+				Label:       "just: " + name,
+				Description: comment,
+				Run:         "just " + shellWord(name),
+				Dir:         dir,
+				File:        path,
+			})
+		}
+		comment, private = "", false
+	}
+	return tasks, scanner.Err()
+}
+
+func hasRequiredParams(params string) bool {
+	for _, param := range strings.Fields(params) {
+		if strings.HasPrefix(param, "*") || strings.Contains(param, "=") {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var makeRulePattern = regexp.MustCompile(`^([^\s:#=][^:#=]*?)\s*::?(?:[^=]|$)`)
+
+// makeAssignPattern matches the POSIX ::= and :::= assignments, which
+// makeRulePattern would otherwise take for double-colon rules.
+var makeAssignPattern = regexp.MustCompile(`^[^\s:#=][^:#=]*?\s*:::?=`)
+
+// MakeTargets returns the explicit targets of a Makefile. A "## text"
+// comment after the target, or on the line above it, becomes the
+// description. Special, pattern and variable targets are skipped.
+func MakeTargets(path string) ([]Task, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dir := filepath.Dir(path)
+	seen := make(map[string]struct{})
+	var tasks []Task
+	var pendingHelp string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if help, ok := strings.CutPrefix(strings.TrimSpace(line), "##"); ok {
+			pendingHelp = strings.TrimSpace(help)
+			continue
+		}
+
+		match := makeRulePattern.FindStringSubmatch(line)
+		if match == nil || makeAssignPattern.MatchString(line) {
+			pendingHelp = ""
+			continue
+		}
+		help := pendingHelp
+		pendingHelp = ""
+		if _, inline, ok := strings.Cut(line, "##"); ok {
+			help = strings.TrimSpace(inline)
+		}
+
+		for _, name := range strings.Fields(match[1]) {
+			if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$()") {
+				continue
+			}
+			if _, dup := seen[name]; dup {
+				continue
+			}
+			seen[name] = struct{}{}
+			tasks = append(tasks, Task{
+				Runner:      "make",
+				Name:        name,
+				Label:       "Make: " + name,
+				Description: help,
+				Run:         "make " + shellWord(name),
+				Dir:         dir,
+				File:        path,
+			})
+		}
+	}
+	return tasks, scanner.Err()
+}
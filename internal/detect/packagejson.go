@@ -0,0 +1,126 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageScripts returns the scripts of a package.json in file order, run
+// through the package manager the project uses.
+func PackageScripts(path string) ([]Task, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	names, err := orderedScriptNames(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta struct {
+		PackageManager string `json:"packageManager"`
+	}
+	_ = json.Unmarshal(data, &meta)
+	dir := filepath.Dir(path)
+	manager := PackageManager(dir, meta.PackageManager)
+
+	present := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		present[name] = struct{}{}
+	}
+
+	tasks := make([]Task, 0, len(names))
+	for _, name := range names {
+		if isLifecycleHook(name, present) {
+			continue
+		}
+		tasks = append(tasks, Task{
+			Runner: manager,
+			Name:   name,
+			Label:  manager + ": " + name,
+			Run:    manager + " run " + shellWord(name),
+			Dir:    dir,
+			File:   path,
+		})
+	}
+	return tasks, nil
+}
+
+// PackageManager picks npm, pnpm, yarn or bun from the packageManager field
+// or, failing that, from the lockfile in dir.
+func PackageManager(dir string, declared string) string {
+	if name, _, _ := strings.Cut(strings.TrimSpace(declared), "@"); name != "" {
+		switch name {
+		case "npm", "pnpm", "yarn", "bun":
+			return name
+		}
+	}
+	switch {
+	case fileExists(filepath.Join(dir, "pnpm-lock.yaml")):
+		return "pnpm"
+	case fileExists(filepath.Join(dir, "yarn.lock")):
+		return "yarn"
+	case fileExists(filepath.Join(dir, "bun.lockb")), fileExists(filepath.Join(dir, "bun.lock")):
+		return "bun"
+	default:
+		return "npm"
+	}
+}
+
+// isLifecycleHook reports pre/post scripts that run on their own around
+// another script, such as "pretest" next to "test".
+func isLifecycleHook(name string, present map[string]struct{}) bool {
+	for _, prefix := range []string{"pre", "post"} {
+		if base, ok := strings.CutPrefix(name, prefix); ok && base != "" {
+			if _, exists := present[base]; exists {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// orderedScriptNames walks the top-level object token by token, since
+// decoding into a map would lose the order scripts are written in.
+func orderedScriptNames(data []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("package.json: expected an object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		if key != "scripts" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil, errors.New("package.json: scripts must be an object")
+		}
+		var names []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, _ := tok.(string)
+			var value string
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	}
+	return nil, nil
+}
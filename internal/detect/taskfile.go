@@ -0,0 +1,91 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TaskfileTasks returns the tasks of a Taskfile.yml with their desc. It
+// reads the tasks map line by line rather than parsing YAML in full, which
+// covers the way Taskfiles are written in practice. Internal tasks are
+// skipped.
+func TaskfileTasks(path string) ([]Task, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dir := filepath.Dir(path)
+	var tasks []Task
+	inTasks := false
+	taskIndent := -1
+	internal := false
+
+	flush := func() {
+		if internal && len(tasks) > 0 {
+			tasks = tasks[:len(tasks)-1]
+		}
+		internal = false
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if indent == 0 {
+			if inTasks {
+				flush()
+			}
+			inTasks = trimmed == "tasks:"
+			taskIndent = -1
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+		if taskIndent < 0 {
+			taskIndent = indent
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch {
+		case indent == taskIndent:
+			flush()
+			tasks = append(tasks, Task{
+				Runner: "task",
+				Name:   key,
+				Label:  "Task: " + key,
+				Run:    "task " + shellWord(key),
+				Dir:    dir,
+				File:   path,
+			})
+		case indent > taskIndent && len(tasks) > 0:
+			switch key {
+			case "desc":
+				if tasks[len(tasks)-1].Description == "" {
+					tasks[len(tasks)-1].Description = value
+				}
+			case "internal":
+				internal = value == "true"
+			}
+		}
+	}
+	if inTasks {
+		flush()
+	}
+	return tasks, scanner.Err()
+}
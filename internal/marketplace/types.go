@@ -1,5 +1,7 @@
 package marketplace
 
+import "encoding/json"
+
 // Spellbook describes a community spellbook manifest.
 type Spellbook struct {
 	Name        string    `json:"name"`
@@ -7,6 +9,9 @@ type Spellbook struct {
 	Author      string    `json:"author"`
 	Version     string    `json:"version"`
 	Commands    []Command `json:"commands"`
+	// When limits where the whole spellbook is offered, using the same
+	// conditions as config commands.
+	When json.RawMessage `json:"when,omitempty"`
 }
 
 // Command describes a single command within a spellbook.
@@ -16,5 +21,14 @@ type Command struct {
 	Label   string `json:"label"`
 	Run     string `json:"run,omitempty"`
 	Script  string `json:"script,omitempty"`
-	Enabled *bool  `json:"enabled,omitempty"`
+	Capture bool   `json:"capture,omitempty"`
+	Danger  string `json:"danger,omitempty"`
+	// Confirm is true/false or "yes"/"type", as in config commands.
+	Confirm json.RawMessage `json:"confirm,omitempty"`
+	When    json.RawMessage `json:"when,omitempty"`
+	Tags    []string        `json:"tags,omitempty"`
+	Enabled *bool           `json:"enabled,omitempty"`
+	// Shortcuts suggests keys for the command, a key or a list as in
+	// config. A suggestion only applies while no other binding uses it.
+	Shortcuts json.RawMessage `json:"shortcuts,omitempty"`
 }
@@ -1,15 +1,19 @@
 package scratchpad
 
 import (
-	"errors"
-	"os"
-	"path/filepath"
 	"strings"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Noudea/glyph/internal/core"
 )
 
+// scratchpadKey stores the note, in scratchpad/scratchpad.md under the
+// workspace.
+const scratchpadKey = "scratchpad.md"
+
 type mode int
 
 const (
@@ -18,7 +22,7 @@ const (
 )
 
 type Model struct {
-	rootPath      string
+	store         core.Store
 	content       string
 	mode          mode
 	input         textarea.Model
@@ -33,7 +37,10 @@ type ViewModel struct {
 	Editing     bool
 	EditorView  string
 	PreviewView string
-	Error       string
+	// Empty is set when there is no note to preview.
+	Empty bool
+	Error string
+	Theme core.Theme
 }
 
 func NewModel() Model {
@@ -48,19 +55,42 @@ func NewModel() Model {
 	}
 }
 
-func (m *Model) SetRoot(path string) error {
-	m.rootPath = strings.TrimSpace(path)
+// SetStore points the model at the workspace store and loads the note.
+func (m *Model) SetStore(store core.Store) {
+	m.store = store
 	m.mode = modePreview
-	m.err = ""
 	m.scroll = 0
 	m.previewHeight = 0
 	m.input.SetValue("")
 	m.input.Blur()
-	if m.rootPath == "" {
-		m.content = ""
+	m.Reload()
+}
+
+// Reload reads the note again unless it is being edited.
+func (m *Model) Reload() {
+	if m.mode == modeEdit {
+		return
+	}
+	scroll := m.scroll
+	if err := m.load(); err != nil {
+		m.err = "load failed: " + err.Error()
+		return
+	}
+	m.err = ""
+	m.scroll = scroll
+}
+
+// Flush saves the note being edited, so quitting keeps it.
+func (m *Model) Flush() error {
+	if m.mode != modeEdit || m.input.Value() == m.content {
 		return nil
 	}
-	return m.load()
+	m.content = m.input.Value()
+	if err := m.save(); err != nil {
+		m.err = err.Error()
+		return err
+	}
+	return nil
 }
 
 func (m Model) Hint() string {
@@ -97,6 +127,10 @@ func (m *Model) ViewModel(width, height int) ViewModel {
 		state.EditorView = m.input.View()
 		return state
 	}
+	if strings.TrimSpace(m.content) == "" {
+		state.Empty = true
+		return state
+	}
 	preview := renderMarkdown(m.content, width)
 	state.PreviewView, m.scroll = previewWindow(preview, m.scroll, contentHeight)
 	return state
@@ -155,6 +189,45 @@ func (m Model) updateEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
+// RunCommand runs a palette action. While editing, the clipboard is pasted
+// into the editor instead of saved.
+func (m Model) RunCommand(id string, args map[string]string) Model {
+	switch id {
+	case "append-clipboard":
+		text, err := clipboard.ReadAll()
+		if err != nil {
+			m.err = "clipboard: " + err.Error()
+			return m
+		}
+		if m.mode == modeEdit {
+			m.input.InsertString(text)
+			return m
+		}
+		m.appendText(text)
+	}
+	return m
+}
+
+// appendText adds text on its own line at the end and saves.
+func (m *Model) appendText(text string) {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return
+	}
+	if m.content != "" && !strings.HasSuffix(m.content, "\n") {
+		m.content += "\n"
+	}
+	m.content += text + "\n"
+	m.input.SetValue(m.content)
+	if err := m.save(); err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.err = ""
+	// Show the end, where the text went; clamped while rendering.
+	m.scroll = int(^uint(0) >> 1)
+}
+
 func (m *Model) beginEdit() {
 	m.mode = modeEdit
 	m.input.SetValue(m.content)
@@ -162,25 +235,16 @@ func (m *Model) beginEdit() {
 }
 
 func (m *Model) load() error {
-	path := scratchpadFilePath(m.rootPath)
-	data, err := os.ReadFile(path)
+	data, ok, err := m.store.Get(scratchpadKey)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-				m.content = ""
-				return err
-			}
-			if err := os.WriteFile(path, nil, 0o644); err != nil {
-				m.content = ""
-				return err
-			}
-			m.content = ""
-			m.input.SetValue("")
-			return nil
-		}
 		m.content = ""
 		return err
 	}
+	if !ok {
+		m.content = ""
+		m.input.SetValue("")
+		return m.store.Set(scratchpadKey, nil)
+	}
 	m.content = string(data)
 	m.input.SetValue(m.content)
 	m.scroll = 0
@@ -188,18 +252,7 @@ func (m *Model) load() error {
 }
 
 func (m *Model) save() error {
-	if m.rootPath == "" {
-		return nil
-	}
-	path := scratchpadFilePath(m.rootPath)
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	return os.WriteFile(path, []byte(m.content), 0o644)
-}
-
-func scratchpadFilePath(rootPath string) string {
-	return filepath.Join(rootPath, "scratchpad", "scratchpad.md")
+	return m.store.Set(scratchpadKey, []byte(m.content))
 }
 
 func (m Model) pageStep() int {
@@ -1,14 +1,19 @@
 package scratchpad
 
 import (
+	"fmt"
+
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Noudea/glyph/internal/core"
 )
 
 type Module struct {
-	model    Model
-	rootPath string
+	model Model
+	store core.Store
+	theme core.Theme
+	// shutdownErr is the save that failed at shutdown, if any.
+	shutdownErr error
 }
 
 func NewModule() core.Module {
@@ -30,27 +35,52 @@ func (m *Module) Init(ctx core.CoreContext) tea.Cmd {
 
 func (m *Module) Update(ctx core.CoreContext, msg tea.Msg) (core.Module, tea.Cmd) {
 	m.ensureContext(ctx)
-	keyMsg, ok := msg.(tea.KeyMsg)
-	if !ok {
-		return m, nil
+	switch msg := msg.(type) {
+	case core.ActivateMsg:
+		m.model.Reload()
+	case core.ShutdownMsg:
+		if err := m.model.Flush(); err != nil {
+			m.shutdownErr = fmt.Errorf("note not saved: %w", err)
+		}
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		m.model, cmd = m.model.Update(msg)
+		return m, cmd
 	}
-	var cmd tea.Cmd
-	m.model, cmd = m.model.Update(keyMsg)
-	return m, cmd
+	return m, nil
 }
 
 func (m *Module) View(width, height int) string {
-	return Render(m.model.ViewModel(width, height))
+	state := m.model.ViewModel(width, height)
+	state.Theme = m.theme
+	return Render(state)
 }
 
 func (m *Module) Hint() string {
 	return m.model.Hint()
 }
 
+func (m *Module) Commands() []core.Command {
+	return []core.Command{
+		{ID: "append-clipboard", Label: "Append clipboard"},
+	}
+}
+
+func (m *Module) RunCommand(ctx core.CoreContext, id string, args map[string]string) (core.Module, tea.Cmd) {
+	m.ensureContext(ctx)
+	m.model = m.model.RunCommand(id, args)
+	return m, nil
+}
+
+func (m *Module) ShutdownErr() error {
+	return m.shutdownErr
+}
+
 func (m *Module) ensureContext(ctx core.CoreContext) {
-	if m.rootPath == ctx.RootPath {
+	m.theme = ctx.Theme
+	if m.store == ctx.Store {
 		return
 	}
-	m.rootPath = ctx.RootPath
-	_ = m.model.SetRoot(ctx.RootPath)
+	m.store = ctx.Store
+	m.model.SetStore(ctx.Store)
 }
@@ -10,12 +10,15 @@ import (
 func Render(state ViewModel) string {
 	lines := make([]string, 0, 2)
 	if state.Error != "" {
-		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B"))
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(state.Theme.Error))
 		lines = append(lines, errStyle.Render("error: "+state.Error))
 	}
-	if state.Editing {
+	switch {
+	case state.Editing:
 		lines = append(lines, state.EditorView)
-	} else {
+	case state.Empty:
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color(state.Theme.Muted)).Render("empty scratchpad"))
+	default:
 		lines = append(lines, state.PreviewView)
 	}
 	content := strings.TrimRight(strings.Join(lines, "\n"), "\n")
@@ -30,10 +33,6 @@ func Render(state ViewModel) string {
 }
 
 func renderMarkdown(content string, width int) string {
-	if strings.TrimSpace(content) == "" {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#7A7A7A")).Render("empty scratchpad")
-	}
-
 	wrap := width
 	if wrap < 20 {
 		wrap = 80
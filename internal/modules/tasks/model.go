@@ -1,16 +1,17 @@
 package tasks
 
 import (
-	"encoding/json"
-	"errors"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Noudea/glyph/internal/core"
 )
 
+// tasksKey stores the task list, in tasks/tasks.json under the workspace.
+const tasksKey = "tasks.json"
+
 type inputMode int
 
 const (
@@ -31,7 +32,9 @@ type Model struct {
 	input     textinput.Model
 	mode      inputMode
 	editIndex int
-	rootPath  string
+	store     core.Store
+	// err is the last failed load or save.
+	err string
 }
 
 type ViewModel struct {
@@ -42,6 +45,8 @@ type ViewModel struct {
 	InputView  string
 	InputLabel string
 	ShowInput  bool
+	Error      string
+	Theme      core.Theme
 }
 
 func NewModel() Model {
@@ -56,18 +61,25 @@ func NewModel() Model {
 	}
 }
 
-func (m *Model) SetRoot(path string) error {
-	m.rootPath = strings.TrimSpace(path)
+// SetStore points the model at the workspace store and loads its tasks.
+func (m *Model) SetStore(store core.Store) {
+	m.store = store
 	m.cursor = 0
 	m.mode = inputNone
 	m.editIndex = -1
 	m.input.SetValue("")
 	m.input.Blur()
-	if m.rootPath == "" {
-		m.tasks = nil
-		return nil
+	m.Reload()
+}
+
+// Reload reads the tasks again, keeping the cursor where it can. Input in
+// progress is left alone.
+func (m *Model) Reload() {
+	if err := m.load(); err != nil {
+		m.err = "load failed: " + err.Error()
+		return
 	}
-	return m.load()
+	m.err = ""
 }
 
 func (m Model) Hint() string {
@@ -97,6 +109,7 @@ func (m Model) ViewModel(width, height int) ViewModel {
 		InputView:  m.input.View(),
 		InputLabel: label,
 		ShowInput:  showInput,
+		Error:      m.err,
 	}
 }
 
@@ -125,6 +138,22 @@ func (m Model) Update(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// RunCommand runs a palette action. "add" adds the title argument, or asks
+// for one when it is missing.
+func (m Model) RunCommand(id string, args map[string]string) Model {
+	switch id {
+	case "add":
+		if title := strings.TrimSpace(args["title"]); title != "" {
+			m.addTask(title)
+			return m
+		}
+		m.beginAdd()
+	case "clear-done":
+		m.clearDone()
+	}
+	return m
+}
+
 func (m Model) updateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.input, cmd = m.input.Update(msg)
@@ -178,7 +207,7 @@ func (m *Model) addTask(title string) {
 	}
 	m.tasks = append(m.tasks, Task{ID: nextID, Title: title})
 	m.cursor = len(m.tasks) - 1
-	_ = m.save()
+	m.persist()
 }
 
 func (m *Model) updateTask(title string) {
@@ -186,7 +215,7 @@ func (m *Model) updateTask(title string) {
 		return
 	}
 	m.tasks[m.editIndex].Title = title
-	_ = m.save()
+	m.persist()
 }
 
 func (m *Model) toggleTask(index int) {
@@ -194,7 +223,7 @@ func (m *Model) toggleTask(index int) {
 		return
 	}
 	m.tasks[index].Done = !m.tasks[index].Done
-	_ = m.save()
+	m.persist()
 }
 
 func (m *Model) deleteTask(index int) {
@@ -208,21 +237,30 @@ func (m *Model) deleteTask(index int) {
 	if len(m.tasks) == 0 {
 		m.cursor = 0
 	}
-	_ = m.save()
+	m.persist()
 }
 
-func (m *Model) load() error {
-	path := tasksFilePath(m.rootPath)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			m.tasks = nil
-			return nil
+func (m *Model) clearDone() {
+	kept := m.tasks[:0]
+	for _, task := range m.tasks {
+		if !task.Done {
+			kept = append(kept, task)
 		}
-		return err
 	}
+	if len(kept) == len(m.tasks) {
+		return
+	}
+	m.tasks = kept
+	if m.cursor >= len(m.tasks) {
+		m.cursor = max(len(m.tasks)-1, 0)
+	}
+	m.persist()
+}
+
+func (m *Model) load() error {
 	var items []Task
-	if err := json.Unmarshal(data, &items); err != nil {
+	if _, err := m.store.GetJSON(tasksKey, &items); err != nil {
+		m.tasks = nil
 		return err
 	}
 	m.tasks = items
@@ -236,22 +274,14 @@ func (m *Model) load() error {
 }
 
 func (m *Model) save() error {
-	if m.rootPath == "" {
-		return nil
-	}
-	path := tasksFilePath(m.rootPath)
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(m.tasks, "", "  ")
-	if err != nil {
-		return err
-	}
-	data = append(data, '\n')
-	return os.WriteFile(path, data, 0o644)
+	return m.store.SetJSON(tasksKey, m.tasks)
 }
 
-func tasksFilePath(rootPath string) string {
-	return filepath.Join(rootPath, "tasks", "tasks.json")
+// persist saves the tasks and keeps a failure for the view.
+func (m *Model) persist() {
+	if err := m.save(); err != nil {
+		m.err = "save failed: " + err.Error()
+		return
+	}
+	m.err = ""
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+
+	"github.com/Noudea/glyph/internal/core"
 )
 
 type taskStyles struct {
@@ -22,10 +24,11 @@ type taskStyles struct {
 	overflow   lipgloss.Style
 	inputLabel lipgloss.Style
 	inputBox   lipgloss.Style
+	err        lipgloss.Style
 }
 
 func Render(state ViewModel) string {
-	styles := newTaskStyles()
+	styles := newTaskStyles(state.Theme)
 	width := state.Width
 
 	total := len(state.Tasks)
@@ -40,6 +43,9 @@ func Render(state ViewModel) string {
 	divider := styles.divider.Render(renderDivider(width))
 
 	availableRows := visibleTaskRows(state.Height, state.ShowInput)
+	if state.Error != "" {
+		availableRows = max(availableRows-1, 1)
+	}
 	visibleTasks, start, end := taskWindow(state.Tasks, state.Cursor, availableRows)
 
 	var b strings.Builder
@@ -47,6 +53,14 @@ func Render(state ViewModel) string {
 	b.WriteString("\n")
 	b.WriteString(divider)
 	b.WriteString("\n")
+	if state.Error != "" {
+		message := "error: " + state.Error
+		if width > 0 {
+			message = ansi.Truncate(message, width, "…")
+		}
+		b.WriteString(styles.err.Render(message))
+		b.WriteString("\n")
+	}
 
 	if total == 0 {
 		b.WriteString(styles.empty.Render("No tasks yet. Press a to add one."))
@@ -98,40 +112,42 @@ func Render(state ViewModel) string {
 	return content
 }
 
-func newTaskStyles() taskStyles {
+func newTaskStyles(theme core.Theme) taskStyles {
 	return taskStyles{
 		title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FF9F68")),
+			Foreground(lipgloss.Color(theme.Accent)),
 		stats: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8A90A6")),
+			Foreground(lipgloss.Color(theme.Muted)),
 		divider: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#5C6475")),
+			Foreground(lipgloss.Color(theme.Subtle)),
 		row: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E8ECF6")),
+			Foreground(lipgloss.Color(theme.Text)),
 		rowDone: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#A1A9BC")),
+			Foreground(lipgloss.Color(theme.Muted)),
 		rowTodo: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E8ECF6")),
+			Foreground(lipgloss.Color(theme.Text)),
 		rowActive: lipgloss.NewStyle().
 			Bold(true),
 		iconDone: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF9F68")).
+			Foreground(lipgloss.Color(theme.Accent)).
 			Bold(true),
 		iconTodo: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#5C6475")).
+			Foreground(lipgloss.Color(theme.Subtle)).
 			Bold(true),
 		empty: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8A90A6")),
+			Foreground(lipgloss.Color(theme.Muted)),
 		overflow: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8A90A6")),
+			Foreground(lipgloss.Color(theme.Muted)),
 		inputLabel: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FF9F68")),
+			Foreground(lipgloss.Color(theme.Accent)),
 		inputBox: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#5C6475")).
+			BorderForeground(lipgloss.Color(theme.Subtle)).
 			Padding(0, 1),
+		err: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Error)),
 	}
 }
 
@@ -0,0 +1,313 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// callTimeout is how long a plugin gets to answer a request before it is
+// stopped.
+const callTimeout = 3 * time.Second
+
+// maxMessageSize bounds one line of plugin output.
+const maxMessageSize = 4 << 20
+
+// stderrTail is how much of a plugin's stderr is kept to explain a crash.
+const stderrTail = 2048
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// client is one running plugin process. Once it stops, every pending and
+// later call fails with the reason.
+type client struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *tailBuffer
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan response
+	// queue holds messages for writeLoop in the order they were sent;
+	// closing has it close stdin once the queue is written.
+	queue   []outgoing
+	closing bool
+	wake    chan struct{}
+	err     error
+	done    chan struct{}
+}
+
+type outgoing struct {
+	method string
+	data   []byte
+}
+
+// pendingCall is a request that has been queued; wait collects its answer.
+type pendingCall struct {
+	client *client
+	method string
+	reply  chan response
+	err    error
+}
+
+func startClient(manifest Manifest, dir string) (*client, error) {
+	cmd := exec.Command(resolveCommand(manifest.Command, dir), manifest.Args...)
+	cmd.Dir = dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	c := &client{
+		cmd:     cmd,
+		stdin:   stdin,
+		stderr:  &tailBuffer{},
+		pending: make(map[int]chan response),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	cmd.Stderr = c.stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go c.read(stdout)
+	go c.writeLoop()
+	return c, nil
+}
+
+// read dispatches responses until the plugin closes stdout or writes
+// something that is not a response, then reaps the process.
+func (c *client) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	var failure error
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var resp response
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			failure = fmt.Errorf("wrote invalid JSON-RPC: %w", err)
+			break
+		}
+		c.mu.Lock()
+		reply, ok := c.pending[resp.ID]
+		delete(c.pending, resp.ID)
+		c.mu.Unlock()
+		if ok {
+			reply <- resp
+		}
+	}
+	if failure == nil {
+		failure = scanner.Err()
+	}
+	if failure != nil {
+		_ = c.cmd.Process.Kill()
+	}
+	waitErr := c.cmd.Wait()
+	switch {
+	case failure != nil:
+	case waitErr != nil:
+		failure = fmt.Errorf("exited: %w", waitErr)
+	default:
+		failure = errors.New("exited")
+	}
+	if tail := c.stderr.String(); tail != "" {
+		failure = fmt.Errorf("%w: %s", failure, tail)
+	}
+	c.stop(failure)
+}
+
+// stop records why the plugin stopped and fails pending calls. The first
+// reason wins.
+func (c *client) stop(reason error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = reason
+	c.pending = nil
+	close(c.done)
+}
+
+func (c *client) stopped() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// send queues a request without blocking. Messages reach the plugin in the
+// order they were sent, whichever goroutine later waits for the answer.
+func (c *client) send(method string, params any) *pendingCall {
+	call := &pendingCall{client: c, method: method}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		call.err = c.err
+		return call
+	}
+	c.nextID++
+	id := c.nextID
+	if err := c.enqueue(request{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		call.err = err
+		return call
+	}
+	call.reply = make(chan response, 1)
+	c.pending[id] = call.reply
+	return call
+}
+
+// wait blocks until the plugin answers and decodes the result into result.
+func (p *pendingCall) wait(result any) error {
+	if p.err != nil {
+		return p.err
+	}
+	c := p.client
+	select {
+	case resp := <-p.reply:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", p.method, err)
+		}
+		return nil
+	case <-c.done:
+		return c.stopped()
+	case <-time.After(callTimeout):
+		c.kill(fmt.Errorf("did not answer %s within %s", p.method, callTimeout))
+		return c.stopped()
+	}
+}
+
+// notify queues a notification, which gets no answer.
+func (c *client) notify(method string, params any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	return c.enqueue(request{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// enqueue hands req to writeLoop. c.mu must be held.
+func (c *client) enqueue(req request) error {
+	if c.closing {
+		return errors.New("shutting down")
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	c.queue = append(c.queue, outgoing{method: req.Method, data: append(data, '\n')})
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// writeLoop writes queued messages one at a time, so a plugin that reads
+// slowly delays its own requests but never the UI. After a failed write it
+// stops; read reports why the plugin went away.
+func (c *client) writeLoop() {
+	for {
+		select {
+		case <-c.wake:
+		case <-c.done:
+			return
+		}
+		c.mu.Lock()
+		queue, closing := c.queue, c.closing
+		c.queue = nil
+		c.mu.Unlock()
+		for _, msg := range queue {
+			if _, err := c.stdin.Write(msg.data); err != nil {
+				return
+			}
+		}
+		if closing {
+			_ = c.stdin.Close()
+			return
+		}
+	}
+}
+
+// kill stops the process for reason.
+func (c *client) kill(reason error) {
+	c.stop(reason)
+	_ = c.cmd.Process.Kill()
+}
+
+// close asks the plugin to shut down, after anything already queued, and
+// gives it callTimeout to exit.
+func (c *client) close() {
+	c.mu.Lock()
+	err := c.err
+	if err == nil {
+		err = c.enqueue(request{JSONRPC: "2.0", Method: "shutdown"})
+		c.closing = true
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return
+	}
+	select {
+	case <-c.done:
+	case <-time.After(callTimeout):
+		c.kill(errors.New("did not exit after shutdown"))
+	}
+}
+
+// tailBuffer keeps the end of what a plugin writes to stderr.
+type tailBuffer struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > stderrTail {
+		b.data = b.data[len(b.data)-stderrTail:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.TrimSpace(string(b.data))
+}
@@ -0,0 +1,272 @@
+package plugin
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/Noudea/glyph/internal/core"
+)
+
+// resizeDelay lets the shell draw at the new size before the plugin is
+// told about it.
+const resizeDelay = 50 * time.Millisecond
+
+// Module runs a plugin process as a core.Module. Events are queued as
+// requests in the order they happen and answered in tea.Cmds, so a slow
+// plugin never blocks the UI.
+type Module struct {
+	manifest Manifest
+	dir      string
+	ctx      core.CoreContext
+
+	client *client
+	frame  Frame
+	// seq numbers requests so a late frame never replaces a newer one.
+	seq     int
+	applied int
+	// err is the last failed request; failed means the process stopped.
+	err    error
+	failed bool
+
+	width, height         int
+	sentWidth, sentHeight int
+}
+
+type frameMsg struct {
+	client *client
+	seq    int
+	frame  Frame
+	err    error
+}
+
+type resizeCheckMsg struct {
+	id string
+}
+
+func newModule(manifest Manifest, dir string) *Module {
+	return &Module{manifest: manifest, dir: dir}
+}
+
+func (m *Module) ID() string {
+	return m.manifest.ID
+}
+
+func (m *Module) Title() string {
+	return m.manifest.Title
+}
+
+func (m *Module) Init(ctx core.CoreContext) tea.Cmd {
+	m.ctx = ctx
+	return m.start()
+}
+
+// start launches the process and sends "initialize".
+func (m *Module) start() tea.Cmd {
+	c, err := startClient(m.manifest, m.dir)
+	if err != nil {
+		m.client = nil
+		m.err = err
+		m.failed = true
+		return nil
+	}
+	m.client = c
+	m.frame = Frame{}
+	m.err = nil
+	m.failed = false
+	params := m.workspaceParams()
+	params["protocolVersion"] = ProtocolVersion
+	params["theme"] = m.ctx.Theme
+	params["width"] = m.width
+	params["height"] = m.height
+	return m.request("initialize", params)
+}
+
+// workspaceParams describes the workspace for "initialize" and
+// "workspaceChanged".
+func (m *Module) workspaceParams() map[string]any {
+	return map[string]any{
+		"rootPath": m.ctx.RootPath,
+		"workspace": map[string]string{
+			"kind":        string(m.ctx.Workspace.Kind),
+			"projectPath": m.ctx.Workspace.ProjectPath,
+		},
+		"storePath": m.ctx.Store.Dir(),
+	}
+}
+
+// notify queues a notification; nothing comes back.
+func (m *Module) notify(method string, params any) {
+	if m.client == nil || m.failed {
+		return
+	}
+	_ = m.client.notify(method, params)
+}
+
+func (m *Module) Update(ctx core.CoreContext, msg tea.Msg) (core.Module, tea.Cmd) {
+	m.ctx = ctx
+	switch msg := msg.(type) {
+	case frameMsg:
+		if msg.client != m.client || msg.seq <= m.applied {
+			return m, nil
+		}
+		m.applied = msg.seq
+		if msg.err != nil {
+			m.err = msg.err
+			m.failed = msg.client.stopped() != nil
+			return m, nil
+		}
+		m.frame = msg.frame
+		m.err = nil
+		if msg.frame.Notify != "" {
+			return m, ctx.Notify(msg.frame.Notify)
+		}
+	case core.ActivateMsg:
+		m.notify("activate", nil)
+	case core.DeactivateMsg:
+		m.notify("deactivate", nil)
+	case core.WorkspaceChangedMsg:
+		m.notify("workspaceChanged", m.workspaceParams())
+	case core.ShutdownMsg:
+		m.Close()
+	case tea.WindowSizeMsg:
+		id := m.ID()
+		return m, tea.Tick(resizeDelay, func(time.Time) tea.Msg {
+			return resizeCheckMsg{id: id}
+		})
+	case resizeCheckMsg:
+		if msg.id == m.ID() && !m.failed && (m.width != m.sentWidth || m.height != m.sentHeight) {
+			return m, m.request("resize", map[string]int{"width": m.width, "height": m.height})
+		}
+	case tea.KeyMsg:
+		if m.failed {
+			if msg.String() == "r" {
+				return m, m.start()
+			}
+			return m, nil
+		}
+		return m, m.request("key", map[string]any{
+			"key":    keyName(msg),
+			"width":  m.width,
+			"height": m.height,
+		})
+	}
+	return m, nil
+}
+
+// request queues method now, so requests keep the order of the events, and
+// waits for the answer in a tea.Cmd that reports it as a frameMsg.
+func (m *Module) request(method string, params any) tea.Cmd {
+	c := m.client
+	if c == nil {
+		return nil
+	}
+	m.seq++
+	seq := m.seq
+	m.sentWidth, m.sentHeight = m.width, m.height
+	call := c.send(method, params)
+	return func() tea.Msg {
+		var frame Frame
+		err := call.wait(&frame)
+		return frameMsg{client: c, seq: seq, frame: frame, err: err}
+	}
+}
+
+func (m *Module) View(width, height int) string {
+	m.width, m.height = width, height
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true)
+
+	var lines []string
+	switch {
+	case m.failed:
+		lines = append(lines,
+			errStyle.Render("✦ "+m.Title()+" stopped"),
+			"",
+			muted.Render(errorText(m.err)),
+			"",
+			muted.Render("Press r to restart it."),
+		)
+	case m.applied == 0 && m.err == nil:
+		lines = append(lines, muted.Render("Starting "+m.Title()+"…"))
+	default:
+		if m.err != nil {
+			lines = append(lines, errStyle.Render("⚠ "+errorText(m.err)))
+		}
+		lines = append(lines, strings.Split(m.frame.View, "\n")...)
+	}
+	return fitView(lines, width, height)
+}
+
+func (m *Module) Hint() string {
+	if m.failed {
+		return m.ID() + ": stopped · r restart"
+	}
+	return m.frame.Hint
+}
+
+// Commands returns the palette entries the plugin offered last.
+func (m *Module) Commands() []core.Command {
+	out := make([]core.Command, 0, len(m.frame.Commands))
+	for _, command := range m.frame.Commands {
+		label := command.Label
+		if label == "" {
+			label = command.ID
+		}
+		out = append(out, core.Command{ID: command.ID, Label: label, Args: command.Args})
+	}
+	return out
+}
+
+func (m *Module) RunCommand(ctx core.CoreContext, id string, args map[string]string) (core.Module, tea.Cmd) {
+	m.ctx = ctx
+	if m.failed {
+		return m, nil
+	}
+	return m, m.request("command", map[string]any{
+		"id":     id,
+		"args":   args,
+		"width":  m.width,
+		"height": m.height,
+	})
+}
+
+// Close shuts the plugin process down.
+func (m *Module) Close() {
+	if m.client != nil {
+		m.client.close()
+	}
+	m.client = nil
+}
+
+// fitView cuts a plugin's output to the app area so it cannot break the
+// surrounding layout.
+func fitView(lines []string, width, height int) string {
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	if width > 0 {
+		for i, line := range lines {
+			lines[i] = ansi.Truncate(line, width, "")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// keyName turns a key message into the key name used in config.
+func keyName(msg tea.KeyMsg) string {
+	if msg.String() == " " {
+		return "space"
+	}
+	return msg.String()
+}
@@ -0,0 +1,142 @@
+// Package plugin runs third-party modules as separate processes.
+//
+// A plugin lives in its own folder under ~/.glyph/plugins with a
+// plugin.json manifest:
+//
+//	{"id": "weather", "title": "Weather", "command": "./weather", "args": []}
+//
+// A command containing a path separator is relative to the plugin folder,
+// which is also its working directory; anything else is looked up in PATH.
+//
+// Glyph talks to the process with JSON-RPC 2.0 over stdio, one JSON object
+// per line. Every request from Glyph is answered with a frame:
+//
+//	initialize {"protocolVersion": 1, "rootPath": "...", "workspace": {"kind": "project", "projectPath": "..."},
+//	            "storePath": "...", "theme": {"accent": "#FF9F68", ...}, "width": 80, "height": 20}
+//	key        {"key": "ctrl+a", "width": 80, "height": 20}
+//	resize     {"width": 80, "height": 20}
+//	command    {"id": "refresh", "args": {"city": "Paris"}, "width": 80, "height": 20}
+//
+//	frame      {"view": "...", "hint": "...", "notify": "...", "commands": [{"id": "...", "label": "...", "args": {}}]}
+//
+// Key names are the ones used in Glyph's config, such as "enter", "up" or
+// "ctrl+a". The view is drawn as-is inside the app area, cut to its size.
+// The commands of the last frame are listed in the palette as
+// "Title: label"; running one sends "command" with its id and args. A
+// frame's notify text is shown in the hint bar, and storePath is a folder
+// the plugin may keep its data in, <rootPath>/data/<id>.
+// Glyph also sends notifications, which get no answer: "activate" and
+// "deactivate" when the app's tab gains or loses focus, "workspaceChanged"
+// with the rootPath, workspace and storePath of "initialize", and
+// "shutdown" before it exits, after which stdin is closed; a plugin must
+// exit once stdin is closed.
+//
+// A plugin that exits, writes something that is not JSON-RPC, or leaves a
+// request unanswered for callTimeout is stopped and shown as failed; Glyph
+// keeps running and the app can be restarted from its tab.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFile names the manifest inside a plugin folder.
+const ManifestFile = "plugin.json"
+
+// ProtocolVersion is sent in "initialize" so plugins can detect changes.
+const ProtocolVersion = 1
+
+// Manifest describes how to start a plugin.
+type Manifest struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Frame is what a plugin returns for every event.
+type Frame struct {
+	View     string    `json:"view"`
+	Hint     string    `json:"hint,omitempty"`
+	Notify   string    `json:"notify,omitempty"`
+	Commands []Command `json:"commands,omitempty"`
+}
+
+// Command is a palette entry a plugin offers.
+type Command struct {
+	ID    string            `json:"id"`
+	Label string            `json:"label"`
+	Args  map[string]string `json:"args,omitempty"`
+}
+
+// Discover reads the manifest of every folder in dir. Plugins are not
+// started until their app is opened. A missing dir has no plugins.
+func Discover(dir string) ([]*Module, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var modules []*Module
+	var problems []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, entry.Name())
+		manifest, err := loadManifest(pluginDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("plugin %s: %w", entry.Name(), err))
+			continue
+		}
+		modules = append(modules, newModule(manifest, pluginDir))
+	}
+	return modules, problems
+}
+
+func loadManifest(dir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	manifest.ID = strings.TrimSpace(manifest.ID)
+	manifest.Command = strings.TrimSpace(manifest.Command)
+	switch {
+	case manifest.ID == "":
+		return Manifest{}, errors.New("manifest needs an id")
+	case strings.ContainsAny(manifest.ID, " \t./\\"):
+		return Manifest{}, errors.New("id cannot contain spaces, dots or slashes: " + manifest.ID)
+	case manifest.Command == "":
+		return Manifest{}, errors.New("manifest needs a command")
+	}
+	if strings.TrimSpace(manifest.Title) == "" {
+		manifest.Title = manifest.ID
+	}
+	return manifest, nil
+}
+
+// resolveCommand makes a command with a path separator relative to the
+// plugin folder.
+func resolveCommand(command, dir string) string {
+	if filepath.IsAbs(command) || !strings.ContainsAny(command, `/\`) {
+		return command
+	}
+	return filepath.Join(dir, command)
+}
@@ -1,12 +1,14 @@
 package registry
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 
 	"github.com/Noudea/glyph/internal/core"
 	scratchpadmodule "github.com/Noudea/glyph/internal/modules/scratchpad"
 	tasksmodule "github.com/Noudea/glyph/internal/modules/tasks"
+	"github.com/Noudea/glyph/internal/plugin"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -27,6 +29,18 @@ func Default() *Registry {
 	return r
 }
 
+// LoadPlugins registers the plugins found in dir. A plugin cannot replace
+// a module that is already registered.
+func (r *Registry) LoadPlugins(dir string) []error {
+	modules, problems := plugin.Discover(dir)
+	for _, module := range modules {
+		if !r.Register(module) {
+			problems = append(problems, fmt.Errorf("plugin %s: id already registered", module.ID()))
+		}
+	}
+	return problems
+}
+
 // ChangedMsg is emitted when the registry contents change.
 type ChangedMsg struct{}
 
@@ -0,0 +1,285 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+	formview "github.com/Noudea/glyph/internal/view/form"
+	launcherview "github.com/Noudea/glyph/internal/view/launcher"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	commandSourceAdhoc = "adhoc"
+	commandAdhocID     = "adhoc.run"
+	groupHistory       = "history"
+)
+
+// adhocCommand wraps shell text typed after ">" so it runs through the same
+// pipeline as configured commands.
+func adhocCommand(run string, group string) core.Command {
+	return core.Command{
+		ID:     commandAdhocID,
+		Label:  run,
+		Kind:   core.CommandExec,
+		Group:  group,
+		Run:    run,
+		Source: commandSourceAdhoc,
+	}
+}
+
+// adhocCommands lists the typed text first, then matching history entries.
+func (m Model) adhocCommands(run string) []core.Command {
+	var out []core.Command
+	if run != "" {
+		out = append(out, adhocCommand(run, groupAdhoc))
+	}
+	needle := strings.ToLower(run)
+	for _, entry := range m.history {
+		if entry != run && strings.Contains(strings.ToLower(entry), needle) {
+			out = append(out, adhocCommand(entry, groupHistory))
+		}
+	}
+	return out
+}
+
+func (m *Model) runAdhoc(command core.Command) tea.Cmd {
+	if err := m.rememberAdhoc(command.Run); err != nil {
+		m.err = "history: " + err.Error()
+	}
+	return m.launchCommand(command)
+}
+
+// launcherHelp documents the launcher query syntax shown for "?".
+func (m Model) launcherHelp() []launcherview.HelpEntry {
+	adhoc := "run text in the shell"
+	if key := m.firstKey("launcher.save"); key != "" {
+		adhoc += "; " + key + " saves it as a command"
+	}
+	return []launcherview.HelpEntry{
+		{Syntax: "words", Text: "match labels, IDs and shortcuts"},
+		{Syntax: "@project", Text: "only project commands (also @global, @spellbook, @detected)"},
+		{Syntax: "@docker", Text: "only commands from a spellbook by name"},
+		{Syntax: "#tag", Text: "only commands tagged tag; several tags must all match"},
+		{Syntax: "group:name", Text: "only sections whose name contains name"},
+		{Syntax: "> text", Text: adhoc},
+		{Syntax: "?", Text: "show this help"},
+	}
+}
+
+func (m Model) launcherHelpState() []launcherview.HelpEntry {
+	if parseLauncherQuery(m.launcherInput.Value()).help {
+		return m.launcherHelp()
+	}
+	return nil
+}
+
+const (
+	saveFieldID = iota
+	saveFieldLabel
+	saveFieldShortcut
+	saveFieldScope
+	saveFieldCount
+)
+
+// saveCommandState is the "save as command" form for an ad-hoc command.
+type saveCommandState struct {
+	run     string
+	inputs  [saveFieldScope]textinput.Model
+	focus   int
+	project bool
+	err     string
+}
+
+var idUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// suggestCommandID derives "user.npm-run-lint" from "npm run lint".
+func suggestCommandID(run string) string {
+	words := strings.Fields(strings.ToLower(run))
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	slug := strings.Trim(idUnsafe.ReplaceAllString(strings.Join(words, "-"), "-"), "-")
+	if slug == "" {
+		slug = "command"
+	}
+	return "user." + slug
+}
+
+func (m *Model) openSaveCommand(run string) {
+	var inputs [saveFieldScope]textinput.Model
+	values := [saveFieldScope]string{suggestCommandID(run), run, ""}
+	placeholders := [saveFieldScope]string{"user.my-command", "Label", "optional, e.g. ctrl+g"}
+	for i := range inputs {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 256
+		input.Width = 40
+		input.Placeholder = placeholders[i]
+		input.SetValue(values[i])
+		inputs[i] = input
+	}
+	inputs[saveFieldID].Focus()
+
+	m.launcherInput.Blur()
+	m.saveCommand = saveCommandState{
+		run:     run,
+		inputs:  inputs,
+		project: m.projectConfigPath != "",
+	}
+	m.mode = ModeSaveCommand
+}
+
+func (m *Model) updateSaveCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := &m.saveCommand
+	action := m.keyAction(scopeForm, msg.String())
+	switch action {
+	case "form.cancel":
+		m.openLauncher()
+		return m, nil
+	case "form.next":
+		m.focusSaveField(form.focus + 1)
+		return m, nil
+	case "form.prev":
+		m.focusSaveField(form.focus - 1)
+		return m, nil
+	case "form.submit":
+		return m, m.submitSaveCommand()
+	}
+
+	if form.focus == saveFieldScope {
+		switch action {
+		case "form.toggle":
+			form.project = !form.project
+		case "form.global":
+			form.project = false
+		case "form.project":
+			form.project = true
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+	form.err = ""
+	return m, cmd
+}
+
+func (m *Model) focusSaveField(index int) {
+	form := &m.saveCommand
+	form.focus = (index%saveFieldCount + saveFieldCount) % saveFieldCount
+	for i := range form.inputs {
+		if i == form.focus {
+			form.inputs[i].Focus()
+		} else {
+			form.inputs[i].Blur()
+		}
+	}
+}
+
+// submitSaveCommand validates the form with the same rules the config
+// loader applies, writes the entry and reloads.
+func (m *Model) submitSaveCommand() tea.Cmd {
+	form := &m.saveCommand
+	id := strings.TrimSpace(form.inputs[saveFieldID].Value())
+	label := strings.TrimSpace(form.inputs[saveFieldLabel].Value())
+	shortcut := normalizeShortcutKey(form.inputs[saveFieldShortcut].Value())
+
+	entry := commandConfig{ID: id, Label: label, Run: form.run}
+	if _, _, err := parseCommandConfig(entry, commandSourceGlobal, "", ""); err != nil {
+		form.err = err.Error()
+		return nil
+	}
+	if strings.ContainsAny(id, " \t") {
+		form.err = "command id cannot contain spaces"
+		return nil
+	}
+	if _, exists := m.findCommandByID(id); exists {
+		form.err = "a command with id " + id + " already exists"
+		return nil
+	}
+	if shortcut != "" {
+		if err := m.shortcutConflict(shortcut); err != nil {
+			form.err = err.Error()
+			return nil
+		}
+	}
+
+	path, scope, err := m.saveTargetPath(form.project)
+	if err != nil {
+		form.err = err.Error()
+		return nil
+	}
+	if err := addCommandToConfig(path, entry); err != nil {
+		form.err = err.Error()
+		return nil
+	}
+	if shortcut != "" {
+		if err := setShortcutInConfig(path, id, []string{shortcut}); err != nil {
+			form.err = err.Error()
+			return nil
+		}
+	}
+
+	if err := m.reloadConfig(); err != nil {
+		m.err = err.Error()
+	}
+	m.openLauncher()
+	return m.notify("Saved " + id + " to the " + scope + " config")
+}
+
+// saveTargetPath returns the config file for the chosen scope, placing a new
+// project config in the start folder when none exists yet.
+func (m *Model) saveTargetPath(project bool) (string, string, error) {
+	if !project {
+		if m.globalConfigPath == "" {
+			return "", "", errors.New("global config is not available")
+		}
+		return m.globalConfigPath, "global", nil
+	}
+	if m.projectConfigPath != "" {
+		return m.projectConfigPath, "project", nil
+	}
+	dir := filepath.Join(m.startDir, ".glyph")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+	return filepath.Join(dir, "config.json"), "project", nil
+}
+
+func (m Model) saveCommandViewState(height int) formview.ViewState {
+	form := m.saveCommand
+	labels := [saveFieldScope]string{"id", "label", "shortcut"}
+	fields := make([]formview.Field, 0, saveFieldCount+1)
+	fields = append(fields, formview.Field{Label: "run", View: form.run})
+	for i, input := range form.inputs {
+		fields = append(fields, formview.Field{
+			Label:   labels[i],
+			View:    input.View(),
+			Focused: form.focus == i,
+		})
+	}
+	scope := "global  ~/.glyph/settings/config.json"
+	if form.project {
+		scope = "project .glyph/config.json"
+	}
+	fields = append(fields, formview.Field{
+		Label:   "scope",
+		View:    scope,
+		Hint:    m.keyHint("form.toggle|form.global|form.project", "to change"),
+		Focused: form.focus == saveFieldScope,
+	})
+	return formview.ViewState{
+		Title:  "Save as command",
+		Fields: fields,
+		Err:    form.err,
+		Footer: m.formHint(),
+		Width:  m.width,
+		Height: height,
+	}
+}
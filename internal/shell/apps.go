@@ -0,0 +1,417 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+	"github.com/Noudea/glyph/internal/plugin"
+	"github.com/Noudea/glyph/internal/registry"
+	topbarview "github.com/Noudea/glyph/internal/view/topbar"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appCommandPrefix starts the palette ID of a module, as in "app.tasks".
+const appCommandPrefix = "app."
+
+const groupApps = "apps"
+
+// pluginDataDir holds plugin stores under the workspace root, apart from
+// Glyph's own folders such as settings and plugins.
+const pluginDataDir = "data"
+
+// appsState tracks the modules opened as apps. Their order and the active
+// one live in core.State.
+type appsState struct {
+	registry *registry.Registry
+	// modules holds each module as last returned by its Update.
+	modules map[string]core.Module
+	// started lists modules whose Init already ran.
+	started map[string]bool
+	// execs holds the processes modules run through CoreContext.Exec.
+	execs   map[int]*trackedProcess
+	nextRun int
+	// workspace is the one modules were last told about.
+	workspace core.Workspace
+}
+
+// appExecFinishedMsg carries the result of a module's Exec back to it.
+type appExecFinishedMsg struct {
+	module string
+	run    int
+	result core.ExecResultMsg
+}
+
+func newAppsState(r *registry.Registry) appsState {
+	apps := appsState{
+		registry: r,
+		modules:  make(map[string]core.Module),
+		started:  make(map[string]bool),
+		execs:    make(map[int]*trackedProcess),
+	}
+	for _, module := range r.Modules() {
+		apps.modules[module.ID()] = module
+	}
+	return apps
+}
+
+// loadApps registers the built-in modules and the plugins installed in
+// ~/.glyph/plugins.
+func loadApps(resolver core.WorkspaceResolver) (appsState, error) {
+	r := registry.Default()
+	var problems []error
+	if root, err := resolver.ResolveGlobal(); err == nil {
+		problems = r.LoadPlugins(filepath.Join(root.RootPath, "plugins"))
+	}
+	return newAppsState(r), errors.Join(problems...)
+}
+
+// closeApps sends ShutdownMsg to the started modules, so plugins exit and
+// modules save, and stops the processes modules started. It returns the
+// failures modules reported.
+func (m *Model) closeApps() error {
+	for _, process := range m.apps.execs {
+		process.Cancel()
+	}
+	var problems []error
+	for _, id := range m.startedApps() {
+		_ = m.deliverApp(id, core.ShutdownMsg{})
+		if reporter, ok := m.apps.modules[id].(core.ShutdownReporter); ok {
+			if err := reporter.ShutdownErr(); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", id, err))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// appCommands lists every registered module as a palette entry.
+func (m Model) appCommands() []core.Command {
+	ids := make([]string, 0, len(m.apps.modules))
+	for id := range m.apps.modules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make([]core.Command, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, core.Command{
+			ID:      appCommandPrefix + id,
+			Label:   m.apps.modules[id].Title(),
+			Kind:    core.CommandApp,
+			Group:   groupApps,
+			Source:  commandSourceManaged,
+			Managed: true,
+			ToolID:  id,
+		})
+	}
+	return out
+}
+
+// appActionCommands lists the actions of every module that offers some,
+// with palette IDs such as "app.tasks.add".
+func (m Model) appActionCommands() []core.Command {
+	ids := make([]string, 0, len(m.apps.modules))
+	for id := range m.apps.modules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var out []core.Command
+	for _, id := range ids {
+		module := m.apps.modules[id]
+		provider, ok := module.(core.CommandProvider)
+		if !ok {
+			continue
+		}
+		for _, action := range provider.Commands() {
+			if strings.TrimSpace(action.ID) == "" {
+				continue
+			}
+			action.ID = appCommandPrefix + id + "." + action.ID
+			action.Label = module.Title() + ": " + action.Label
+			action.Kind = core.CommandAction
+			action.Group = groupApps
+			action.Source = commandSourceManaged
+			action.Managed = true
+			action.ToolID = id
+			out = append(out, action)
+		}
+	}
+	return out
+}
+
+// findAppAction returns the module action behind a palette ID.
+func (m Model) findAppAction(commandID string) (core.Command, bool) {
+	if !strings.HasPrefix(commandID, appCommandPrefix) {
+		return core.Command{}, false
+	}
+	for _, action := range m.appActionCommands() {
+		if action.ID == commandID {
+			return action, true
+		}
+	}
+	return core.Command{}, false
+}
+
+// runAppAction opens the app that owns action, then hands the action to
+// it once the app has started.
+func (m *Model) runAppAction(action core.Command) tea.Cmd {
+	moduleID := action.ToolID
+	id := strings.TrimPrefix(action.ID, appCommandPrefix+moduleID+".")
+	initCmd := m.openApp(moduleID)
+	provider, ok := m.apps.modules[moduleID].(core.CommandProvider)
+	if !ok {
+		return initCmd
+	}
+	args := make(map[string]string, len(action.Args))
+	for key, value := range action.Args {
+		args[key] = value
+	}
+	updated, cmd := provider.RunCommand(m.moduleContext(moduleID), id, args)
+	if updated != nil {
+		m.apps.modules[moduleID] = updated
+	}
+	return tea.Sequence(initCmd, cmd)
+}
+
+// appCommandModule returns the module a palette ID opens.
+func (m Model) appCommandModule(commandID string) (string, bool) {
+	id, ok := strings.CutPrefix(commandID, appCommandPrefix)
+	if !ok {
+		return "", false
+	}
+	_, exists := m.apps.modules[id]
+	return id, exists
+}
+
+// moduleContext roots module id in the project's .glyph folder when there
+// is one and in ~/.glyph otherwise.
+func (m Model) moduleContext(id string) core.CoreContext {
+	ctx := core.CoreContext{ModuleID: id, Theme: core.DefaultTheme()}
+	if m.projectConfigPath != "" {
+		root := filepath.Dir(m.projectConfigPath)
+		ctx.Workspace = core.Workspace{
+			Kind:        core.WorkspaceProject,
+			RootPath:    root,
+			ProjectPath: filepath.Dir(root),
+		}
+	} else if global, err := m.resolver.ResolveGlobal(); err == nil {
+		ctx.Workspace = global
+	}
+	ctx.RootPath = ctx.Workspace.RootPath
+	ctx.Store = core.NewStore(ctx.RootPath, id)
+	if _, ok := m.apps.modules[id].(*plugin.Module); ok {
+		ctx.Store = core.NewStore(filepath.Join(ctx.RootPath, pluginDataDir), id)
+	}
+	return ctx
+}
+
+// execForModule runs a command for a module with its output captured and
+// answers the module with the result.
+func (m *Model) execForModule(msg core.ExecMsg) tea.Cmd {
+	process, err := shellExecCommand(msg.Command, m.startDir)
+	if err != nil {
+		return m.deliverApp(msg.ModuleID, core.ExecResultMsg{ID: msg.ID, Err: err})
+	}
+	output := newOutputBuffer(nil)
+	process.Stdout = output
+	process.Stderr = output
+
+	m.apps.nextRun++
+	run := m.apps.nextRun
+	events := m.events
+	tracked, err := startTrackedProcess(process, msg.Command.Timeout, func(err error) {
+		events <- appExecFinishedMsg{
+			module: msg.ModuleID,
+			run:    run,
+			result: core.ExecResultMsg{ID: msg.ID, Output: strings.Join(output.Lines(), "\n"), Err: err},
+		}
+	})
+	if err != nil {
+		return m.deliverApp(msg.ModuleID, core.ExecResultMsg{ID: msg.ID, Err: err})
+	}
+	m.apps.execs[run] = tracked
+	return nil
+}
+
+func (m *Model) handleAppExecFinished(msg appExecFinishedMsg) tea.Cmd {
+	delete(m.apps.execs, msg.run)
+	return m.deliverApp(msg.module, msg.result)
+}
+
+// deliverApp hands msg to module id whether or not it is open.
+func (m *Model) deliverApp(id string, msg tea.Msg) tea.Cmd {
+	module, ok := m.apps.modules[id]
+	if !ok {
+		return nil
+	}
+	updated, cmd := module.Update(m.moduleContext(id), msg)
+	if updated != nil {
+		m.apps.modules[id] = updated
+	}
+	return cmd
+}
+
+// openApp adds a module to the open apps, or focuses it when it is open.
+func (m *Model) openApp(id string) tea.Cmd {
+	if _, ok := m.apps.modules[id]; !ok {
+		return nil
+	}
+	m.launcherInput.Blur()
+	m.mode = ModeMain
+	if !slices.Contains(m.state.OpenApps, id) {
+		m.state.OpenApps = append(m.state.OpenApps, id)
+	}
+	return tea.Sequence(m.startApp(id), m.focusApp(id))
+}
+
+// startApp runs the Init of module id the first time it opens.
+func (m *Model) startApp(id string) tea.Cmd {
+	module, ok := m.apps.modules[id]
+	if !ok || m.apps.started[id] {
+		return nil
+	}
+	m.apps.started[id] = true
+	return module.Init(m.moduleContext(id))
+}
+
+// restoreApps reopens the apps of the last session, dropping modules that
+// are no longer installed.
+func (m *Model) restoreApps() tea.Cmd {
+	open := m.state.OpenApps[:0]
+	for _, id := range m.state.OpenApps {
+		if _, ok := m.apps.modules[id]; ok && !slices.Contains(open, id) {
+			open = append(open, id)
+		}
+	}
+	m.state.OpenApps = open
+	active := m.state.ActiveApp
+	m.state.ActiveApp = ""
+	if len(open) == 0 {
+		return nil
+	}
+	if !slices.Contains(open, active) {
+		active = open[0]
+	}
+	cmds := make([]tea.Cmd, 0, len(open)+1)
+	for _, id := range open {
+		cmds = append(cmds, m.startApp(id))
+	}
+	return tea.Sequence(tea.Batch(cmds...), m.focusApp(active))
+}
+
+// focusApp makes id the active app, or leaves none when id is empty, and
+// tells both apps about the switch.
+func (m *Model) focusApp(id string) tea.Cmd {
+	previous := m.state.ActiveApp
+	if previous == id {
+		return nil
+	}
+	m.state.ActiveApp = id
+	var cmds []tea.Cmd
+	if previous != "" {
+		cmds = append(cmds, m.deliverApp(previous, core.DeactivateMsg{}))
+	}
+	if id != "" {
+		cmds = append(cmds, m.deliverApp(id, core.ActivateMsg{}))
+	}
+	m.saveSession()
+	return tea.Batch(cmds...)
+}
+
+// closeApp removes the active app and focuses its neighbour.
+func (m *Model) closeApp() tea.Cmd {
+	i := slices.Index(m.state.OpenApps, m.state.ActiveApp)
+	if i < 0 {
+		return nil
+	}
+	m.state.OpenApps = slices.Delete(m.state.OpenApps, i, i+1)
+	next := ""
+	if len(m.state.OpenApps) > 0 {
+		next = m.state.OpenApps[min(i, len(m.state.OpenApps)-1)]
+	}
+	return m.focusApp(next)
+}
+
+// cycleApp focuses the next or previous open app.
+func (m *Model) cycleApp(step int) tea.Cmd {
+	count := len(m.state.OpenApps)
+	if count == 0 {
+		return nil
+	}
+	index := max(slices.Index(m.state.OpenApps, m.state.ActiveApp), 0)
+	return m.focusApp(m.state.OpenApps[((index+step)%count+count)%count])
+}
+
+// syncWorkspace tells the started modules when their workspace changed
+// since the last check, for example after a project config appeared.
+func (m *Model) syncWorkspace() tea.Cmd {
+	workspace := m.moduleContext("").Workspace
+	if workspace == m.apps.workspace {
+		return nil
+	}
+	m.apps.workspace = workspace
+	var cmds []tea.Cmd
+	for _, id := range m.startedApps() {
+		cmds = append(cmds, m.deliverApp(id, core.WorkspaceChangedMsg{Workspace: workspace}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// startedApps lists the modules whose Init ran, in a stable order.
+func (m Model) startedApps() []string {
+	ids := make([]string, 0, len(m.apps.started))
+	for id := range m.apps.started {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (m Model) activeModule() core.Module {
+	if m.state == nil || m.state.ActiveApp == "" {
+		return nil
+	}
+	return m.apps.modules[m.state.ActiveApp]
+}
+
+// broadcastApps hands msg to every open app, so replies to an app's own
+// commands reach it while another app has focus.
+func (m *Model) broadcastApps(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, id := range m.state.OpenApps {
+		cmds = append(cmds, m.deliverApp(id, msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateApp hands msg to the active app.
+func (m *Model) updateApp(msg tea.Msg) tea.Cmd {
+	if m.activeModule() == nil {
+		return nil
+	}
+	return m.deliverApp(m.state.ActiveApp, msg)
+}
+
+// appTabs returns the open apps in tab order for the topbar.
+func (m Model) appTabs() []core.Command {
+	tabs := make([]core.Command, 0, len(m.state.OpenApps))
+	for _, id := range m.state.OpenApps {
+		if module, ok := m.apps.modules[id]; ok {
+			tabs = append(tabs, core.Command{ID: id, Label: module.Title(), Kind: core.CommandApp})
+		}
+	}
+	return tabs
+}
+
+func (m Model) topbarViewState() topbarview.ViewState {
+	return topbarview.ViewState{
+		Width:     m.width,
+		Title:     m.workspaceTitle(),
+		Tabs:      m.appTabs(),
+		ActiveApp: m.state.ActiveApp,
+	}
+}
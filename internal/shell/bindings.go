@@ -0,0 +1,217 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	keysview "github.com/Noudea/glyph/internal/view/keys"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const commandKeysOpen = "keys.open"
+
+// Binding origins, from lowest to highest precedence.
+const (
+	originDefault   = "default"
+	originSpellbook = "spellbook"
+	originGlobal    = "global"
+	originProject   = "project"
+)
+
+// bindingScopeCommand is the scope of command shortcuts, which fire in the
+// main view and the palette.
+const bindingScopeCommand = "command"
+
+// bindingLayer is one source of bindings: the defaults or a config
+// section, keyed by command or action ID.
+type bindingLayer struct {
+	origin   string
+	bindings map[string][]string
+}
+
+// bindingRecord is one binding as configured and what became of it.
+type bindingRecord struct {
+	// Key is the normalized key; Raw is the key as written when it differs.
+	Key    string
+	Raw    string
+	Target string
+	Scope  string
+	Origin string
+	// Dropped explains why the binding is not in effect, as in "is
+	// reserved for global.quit". It is empty for effective bindings.
+	Dropped string
+	// quiet drops are expected, such as a replaced default, and are listed
+	// without being reported as config problems.
+	quiet bool
+}
+
+func newBindingRecord(raw, key, target, scope, origin string) bindingRecord {
+	record := bindingRecord{Key: key, Target: target, Scope: scope, Origin: origin}
+	if raw != key {
+		record.Raw = raw
+	}
+	return record
+}
+
+// suggested reports bindings a spellbook proposes, which yield to any
+// other binding.
+func (r bindingRecord) suggested() bool {
+	return r.Origin == originSpellbook
+}
+
+// written returns the key the way config spelled it.
+func (r bindingRecord) written() string {
+	if r.Raw != "" {
+		return r.Raw
+	}
+	return r.Key
+}
+
+// recordErrors reports the dropped bindings that point at a config problem.
+func recordErrors(kind string, records []bindingRecord) []error {
+	var errs []error
+	for _, record := range records {
+		if record.Dropped != "" && !record.quiet {
+			errs = append(errs, fmt.Errorf("%s %s of %s %s", kind, record.written(), record.Target, record.Dropped))
+		}
+	}
+	return errs
+}
+
+// bindingRecords lists command shortcuts first, then actions in help
+// order, each sorted by key so colliding bindings sit together.
+func (m Model) bindingRecords() []bindingRecord {
+	records := make([]bindingRecord, 0, len(m.shortcutRecords)+len(m.keyRecords))
+	records = append(records, m.shortcutRecords...)
+	records = append(records, m.keyRecords...)
+	rank := map[string]int{bindingScopeCommand: 0}
+	for i, entry := range keyScopeTitles {
+		rank[string(entry.scope)] = i + 1
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		rankA, knownA := rank[a.Scope]
+		rankB, knownB := rank[b.Scope]
+		if !knownA {
+			rankA = len(rank)
+		}
+		if !knownB {
+			rankB = len(rank)
+		}
+		if rankA != rankB {
+			return rankA < rankB
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Dropped == "" && b.Dropped != ""
+	})
+	return records
+}
+
+// keysInspectorState is the keymap inspector.
+type keysInspectorState struct {
+	cursor      int
+	droppedOnly bool
+}
+
+func (m Model) inspectorRecords() []bindingRecord {
+	records := m.bindingRecords()
+	if !m.keysInspector.droppedOnly {
+		return records
+	}
+	dropped := records[:0]
+	for _, record := range records {
+		if record.Dropped != "" {
+			dropped = append(dropped, record)
+		}
+	}
+	return dropped
+}
+
+func (m *Model) openKeysInspector() {
+	m.launcherInput.Blur()
+	m.keysInspector = keysInspectorState{}
+	m.mode = ModeKeys
+}
+
+func (m *Model) updateKeysInspector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	inspector := &m.keysInspector
+	switch m.keyAction(scopeKeys, msg.String()) {
+	case "keys.back":
+		m.openLauncher()
+	case "keys.up":
+		if inspector.cursor > 0 {
+			inspector.cursor--
+		}
+	case "keys.down":
+		if inspector.cursor < len(m.inspectorRecords())-1 {
+			inspector.cursor++
+		}
+	case "keys.dropped":
+		inspector.droppedOnly = !inspector.droppedOnly
+		inspector.cursor = 0
+	}
+	return m, nil
+}
+
+func (m Model) keysInspectorViewState(height int) keysview.ViewState {
+	records := m.inspectorRecords()
+	rows := make([]keysview.Row, len(records))
+	for i, record := range records {
+		rows[i] = keysview.Row{
+			Key:     record.Key,
+			Raw:     record.Raw,
+			Target:  record.Target,
+			Scope:   record.Scope,
+			Origin:  record.Origin,
+			Dropped: record.Dropped,
+		}
+	}
+	dropped := 0
+	for _, record := range m.bindingRecords() {
+		if record.Dropped != "" {
+			dropped++
+		}
+	}
+	return keysview.ViewState{
+		Rows:        rows,
+		Cursor:      m.keysInspector.cursor,
+		Dropped:     dropped,
+		DroppedOnly: m.keysInspector.droppedOnly,
+		Footer:      m.keysFooter(),
+		Width:       m.width,
+		Height:      height,
+	}
+}
+
+func (m Model) keysFooter() string {
+	toggle := "dropped only"
+	if m.keysInspector.droppedOnly {
+		toggle = "show all"
+	}
+	return m.keyHints("keys.up|keys.down", "move", "keys.dropped", toggle, "keys.back", "back")
+}
+
+// printBindings writes the binding report of `glyph keys`.
+func printBindings(out io.Writer, records []bindingRecord, droppedOnly bool) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTARGET\tSCOPE\tORIGIN\tSTATUS")
+	for _, record := range records {
+		if droppedOnly && record.Dropped == "" {
+			continue
+		}
+		key := record.Key
+		if record.Raw != "" {
+			key += " (" + record.Raw + ")"
+		}
+		status := "active"
+		if record.Dropped != "" {
+			status = "dropped: " + record.Dropped
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", key, record.Target, record.Scope, record.Origin, status)
+	}
+	w.Flush()
+}
@@ -0,0 +1,305 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+	outputview "github.com/Noudea/glyph/internal/view/output"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type captureState struct {
+	command core.Command
+	output  *outputBuffer
+	process *trackedProcess
+	run     int
+	running bool
+	err     error
+
+	cursor int
+	follow bool
+
+	search    textinput.Model
+	searching bool
+	query     string
+}
+
+type captureFinishedMsg struct {
+	run int
+	err error
+}
+
+// startCapture runs command with piped output and shows it in the output
+// pane instead of handing over the terminal.
+func (m *Model) startCapture(command core.Command) tea.Cmd {
+	process, err := shellExecCommand(command, m.startDir)
+	if err != nil {
+		m.err = fmt.Sprintf("%s failed: %s", command.Label, err)
+		return nil
+	}
+
+	search := m.capture.search
+	if search.Prompt == "" {
+		search = textinput.New()
+		search.Prompt = "/ "
+		search.Placeholder = "search"
+		search.CharLimit = 128
+	}
+	search.Blur()
+
+	output := newOutputBuffer(m.outputNotifier())
+	process.Stdout = output
+	process.Stderr = output
+
+	run := m.capture.run + 1
+	m.capture = captureState{
+		command: command,
+		output:  output,
+		run:     run,
+		running: true,
+		follow:  true,
+		search:  search,
+		query:   m.capture.query,
+	}
+	m.err = ""
+	m.launcherInput.Blur()
+	m.mode = ModeOutput
+
+	events := m.events
+	tracked, err := startTrackedProcess(process, command.Timeout, func(err error) {
+		events <- captureFinishedMsg{run: run, err: err}
+	})
+	if err != nil {
+		m.capture.running = false
+		m.capture.err = err
+		return nil
+	}
+	m.capture.process = tracked
+	return nil
+}
+
+func (m *Model) handleCaptureFinished(msg captureFinishedMsg) tea.Cmd {
+	if msg.run != m.capture.run {
+		return nil
+	}
+	m.capture.running = false
+	m.capture.err = msg.err
+	if msg.err != nil {
+		return m.notify(m.capture.command.Label + " failed: " + formatCommandExecError(msg.err))
+	}
+	return nil
+}
+
+func (m *Model) captureLines() []string {
+	if m.capture.output == nil {
+		return nil
+	}
+	lines := m.capture.output.Lines()
+	for i, line := range lines {
+		lines[i] = sanitizeOutputLine(line)
+	}
+	return lines
+}
+
+// sanitizeOutputLine keeps only what a carriage return would have left on
+// screen, so progress bars collapse to their final state.
+func sanitizeOutputLine(line string) string {
+	if idx := strings.LastIndexByte(line, '\r'); idx >= 0 {
+		line = line[idx+1:]
+	}
+	return strings.ReplaceAll(line, "\t", "    ")
+}
+
+func (m *Model) captureMatches(lines []string) []int {
+	query := strings.ToLower(strings.TrimSpace(m.capture.query))
+	if query == "" {
+		return nil
+	}
+	var matches []int
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func (m *Model) updateCapture(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	lines := m.captureLines()
+	last := len(lines) - 1
+	if last < 0 {
+		last = 0
+	}
+	if m.capture.follow {
+		m.capture.cursor = last
+	}
+
+	if m.capture.searching {
+		switch m.keyAction(scopeSearch, msg.String()) {
+		case "search.cancel":
+			m.capture.searching = false
+			m.capture.search.Blur()
+			return m, nil
+		case "search.find":
+			m.capture.searching = false
+			m.capture.search.Blur()
+			m.capture.query = m.capture.search.Value()
+			m.jumpToMatch(lines, 1, true)
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.capture.search, cmd = m.capture.search.Update(msg)
+		return m, cmd
+	}
+
+	page := outputview.VisibleRows(m.height) - 1
+	if page < 1 {
+		page = 1
+	}
+
+	switch m.keyAction(scopeOutput, msg.String()) {
+	case "output.back":
+		// Leaving the pane does not stop the run; re-opening the command
+		// starts a new one, so cancel the old run to avoid orphaning it.
+		if m.capture.running && m.capture.process != nil {
+			m.capture.process.Cancel()
+		}
+		m.openLauncher()
+	case "output.up":
+		m.moveCaptureCursor(-1, last)
+	case "output.down":
+		m.moveCaptureCursor(1, last)
+	case "output.page-up":
+		m.moveCaptureCursor(-page, last)
+	case "output.page-down":
+		m.moveCaptureCursor(page, last)
+	case "output.top":
+		m.moveCaptureCursor(-len(lines), last)
+	case "output.bottom":
+		m.capture.cursor = last
+		m.capture.follow = true
+	case "output.search":
+		m.capture.searching = true
+		m.capture.search.SetValue(m.capture.query)
+		m.capture.search.CursorEnd()
+		return m, m.capture.search.Focus()
+	case "output.next-match":
+		m.jumpToMatch(lines, 1, false)
+	case "output.prev-match":
+		m.jumpToMatch(lines, -1, false)
+	case "output.copy-line":
+		if m.capture.cursor >= 0 && m.capture.cursor < len(lines) {
+			return m, m.copyToClipboard(ansi.Strip(lines[m.capture.cursor]), "line")
+		}
+	case "output.copy-all":
+		plain := make([]string, len(lines))
+		for i, line := range lines {
+			plain[i] = ansi.Strip(line)
+		}
+		return m, m.copyToClipboard(strings.Join(plain, "\n"), "output")
+	case "output.rerun":
+		if !m.capture.running {
+			return m, m.startCapture(m.capture.command)
+		}
+	case "output.cancel":
+		if m.capture.running && m.capture.process != nil {
+			m.capture.process.Cancel()
+			return m, m.notify("canceling " + m.capture.command.Label)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) moveCaptureCursor(delta, last int) {
+	m.capture.cursor += delta
+	if m.capture.cursor < 0 {
+		m.capture.cursor = 0
+	}
+	if m.capture.cursor > last {
+		m.capture.cursor = last
+	}
+	m.capture.follow = m.capture.cursor == last && delta > 0
+}
+
+// jumpToMatch moves the cursor to the next match in direction dir. When
+// inclusive is set the current line counts as a match.
+func (m *Model) jumpToMatch(lines []string, dir int, inclusive bool) {
+	matches := m.captureMatches(lines)
+	if len(matches) == 0 {
+		return
+	}
+	target := -1
+	if dir > 0 {
+		for _, index := range matches {
+			if index > m.capture.cursor || (inclusive && index == m.capture.cursor) {
+				target = index
+				break
+			}
+		}
+		if target < 0 {
+			target = matches[0]
+		}
+	} else {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < m.capture.cursor {
+				target = matches[i]
+				break
+			}
+		}
+		if target < 0 {
+			target = matches[len(matches)-1]
+		}
+	}
+	m.capture.cursor = target
+	m.capture.follow = false
+}
+
+func (m *Model) copyToClipboard(text, what string) tea.Cmd {
+	if err := clipboard.WriteAll(text); err != nil {
+		return m.notify("copy failed: " + err.Error())
+	}
+	return m.notify("copied " + what + " to clipboard")
+}
+
+func (m *Model) captureViewState(height int) outputview.ViewState {
+	lines := m.captureLines()
+	cursor := m.capture.cursor
+	if m.capture.follow {
+		cursor = len(lines) - 1
+	}
+
+	status := "running…"
+	if !m.capture.running {
+		status = "done"
+		if m.capture.err != nil {
+			status = formatCommandExecError(m.capture.err)
+		}
+	}
+
+	matches := make(map[int]bool)
+	for _, index := range m.captureMatches(lines) {
+		matches[index] = true
+	}
+
+	state := outputview.ViewState{
+		Title:   m.capture.command.Label,
+		Lines:   lines,
+		Cursor:  cursor,
+		Status:  status,
+		Failed:  m.capture.err != nil,
+		Running: m.capture.running,
+		Matches: matches,
+		Query:   m.capture.query,
+		Footer:  m.outputFooter(),
+		Width:   m.width,
+		Height:  height,
+	}
+	if m.capture.searching {
+		state.SearchView = m.capture.search.View()
+	}
+	return state
+}
@@ -0,0 +1,204 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Noudea/glyph/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type chainStepStatus string
+
+const (
+	chainStepPending chainStepStatus = "pending"
+	chainStepRunning chainStepStatus = "running"
+	chainStepOK      chainStepStatus = "ok"
+	chainStepFailed  chainStepStatus = "failed"
+	chainStepSkipped chainStepStatus = "skipped"
+)
+
+type chainStepRun struct {
+	command         core.Command
+	continueOnError bool
+	confirmed       bool
+	status          chainStepStatus
+	err             error
+}
+
+type chainState struct {
+	command core.Command
+	steps   []chainStepRun
+	current int
+	done    bool
+	failed  bool
+}
+
+// startChain resolves every step up front so a typo in a referenced ID fails
+// before anything runs, then launches the first step.
+func (m *Model) startChain(command core.Command) tea.Cmd {
+	steps := make([]chainStepRun, 0, len(command.Steps))
+	for i, step := range command.Steps {
+		resolved, err := m.resolveChainStep(command, step)
+		if err != nil {
+			m.err = fmt.Sprintf("%s step %d: %s", command.Label, i+1, err)
+			return nil
+		}
+		steps = append(steps, chainStepRun{
+			command:         resolved,
+			continueOnError: step.ContinueOnError,
+			status:          chainStepPending,
+		})
+	}
+
+	m.err = ""
+	m.launcherInput.Blur()
+	m.chain = chainState{command: command, steps: steps}
+	m.mode = ModeChain
+	return m.runChainStep()
+}
+
+func (m *Model) resolveChainStep(chain core.Command, step core.ChainStep) (core.Command, error) {
+	command := step.Inline
+	if step.Ref != "" {
+		ref, ok := m.findCommandByID(step.Ref)
+		if !ok {
+			return core.Command{}, errors.New("command not found: " + step.Ref)
+		}
+		if ref.Kind == core.CommandChain {
+			return core.Command{}, errors.New("nested chains are not supported: " + step.Ref)
+		}
+		// Inline steps got the chain's env when the config was parsed; a
+		// referenced command's own env wins over the chain's.
+		command = ref
+		command.EnvFiles = append(append([]string(nil), chain.EnvFiles...), ref.EnvFiles...)
+		command.Env = mergeEnvMaps(chain.Env, ref.Env)
+	}
+
+	// Shared arguments reach every step as environment variables; argv steps
+	// have no shell, so their ${NAME} references are expanded here.
+	command.Env = mergeEnvMaps(chain.Args, command.Env)
+	if len(command.Argv) > 0 && len(chain.Args) > 0 {
+		argv := make([]string, len(command.Argv))
+		for i, arg := range command.Argv {
+			argv[i] = os.Expand(arg, func(name string) string {
+				if value, ok := chain.Args[name]; ok {
+					return value
+				}
+				return os.Getenv(name)
+			})
+		}
+		command.Argv = argv
+	}
+
+	// The progress view reports each result, so steps only stop for the user
+	// when something goes wrong unless they ask otherwise.
+	command.RunMode = core.RunTerminal
+	if command.After.Pause == "" {
+		command.After.Pause = core.PauseOnFailure
+	}
+	return command, nil
+}
+
+func (m *Model) runChainStep() tea.Cmd {
+	index := m.chain.current
+	if index >= len(m.chain.steps) {
+		m.finishChain()
+		return nil
+	}
+	step := &m.chain.steps[index]
+	// A step keeps its own confirm gate, so a chain cannot run a guarded
+	// command without asking.
+	if step.command.Confirm != core.ConfirmNone && !step.confirmed {
+		m.requestConfirm(step.command)
+		m.confirm.chainStep = true
+		return nil
+	}
+	step.status = chainStepRunning
+
+	chainID := m.chain.command.ID
+	process, err := shellExecCommand(step.command, m.startDir)
+	finished := func(err error) tea.Msg {
+		msg := newCommandFinishedMsg(chainID, err)
+		msg.Chain = true
+		msg.Step = index
+		return msg
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return finished(err)
+		}
+	}
+	return tea.Exec(newExecProcess(process, step.command), finished)
+}
+
+func (m *Model) handleChainStepFinished(msg commandFinishedMsg) tea.Cmd {
+	if m.chain.command.ID != msg.CommandID || msg.Step != m.chain.current || m.chain.done {
+		return nil
+	}
+	step := &m.chain.steps[msg.Step]
+	step.err = msg.Err
+	if msg.Err == nil {
+		step.status = chainStepOK
+	} else {
+		step.status = chainStepFailed
+		m.chain.failed = true
+		if m.chain.command.StopOnError && !step.continueOnError {
+			for i := msg.Step + 1; i < len(m.chain.steps); i++ {
+				m.chain.steps[i].status = chainStepSkipped
+			}
+			m.finishChain()
+			return nil
+		}
+	}
+	m.chain.current++
+	return m.runChainStep()
+}
+
+// cancelChainStep stops the chain when the user declines a step's prompt.
+func (m *Model) cancelChainStep() {
+	m.mode = ModeChain
+	step := &m.chain.steps[m.chain.current]
+	step.status = chainStepFailed
+	step.err = errors.New("canceled")
+	m.chain.failed = true
+	for i := m.chain.current + 1; i < len(m.chain.steps); i++ {
+		m.chain.steps[i].status = chainStepSkipped
+	}
+	m.finishChain()
+}
+
+func (m *Model) finishChain() {
+	m.chain.done = true
+	if !m.chain.failed {
+		m.err = ""
+		return
+	}
+	for i, step := range m.chain.steps {
+		if step.status == chainStepFailed {
+			m.err = fmt.Sprintf("%s failed at step %d (%s): %s", m.chain.command.Label, i+1, step.command.Label, formatCommandExecError(step.err))
+			return
+		}
+	}
+}
+
+func (m *Model) updateChain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.chain.done {
+		return m, nil
+	}
+	switch m.keyAction(scopeChain, msg.String()) {
+	case "chain.return":
+		if !m.chain.failed && m.chain.command.After.Return == core.ReturnQuit {
+			return m, m.quit()
+		}
+		if !m.chain.failed && m.chain.command.After.Return == core.ReturnMain {
+			m.mode = ModeMain
+			return m, nil
+		}
+		m.openLauncher()
+	case "chain.rerun":
+		return m, m.startChain(m.chain.command)
+	}
+	return m, nil
+}
@@ -0,0 +1,212 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+)
+
+const cliUsage = `usage: glyph [command]
+
+With no command, glyph opens the palette.
+
+commands:
+  run [--dry-run] [--yes] <id>   run a command, or print what it would run
+  keys [--dropped]               list key bindings, where they come from and
+                                 why any were dropped
+`
+
+// RunCLI handles glyph's non-interactive subcommands and returns the process
+// exit code.
+func RunCLI(args []string, resolver core.WorkspaceResolver, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, cliUsage)
+		return 2
+	}
+	switch args[0] {
+	case "run":
+		return runCLI(args[1:], resolver, stdout, stderr)
+	case "keys":
+		return keysCLI(args[1:], resolver, stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, cliUsage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "glyph: unknown command %q\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+func runCLI(args []string, resolver core.WorkspaceResolver, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dryRun := flags.Bool("dry-run", false, "print the resolved command without running it")
+	yes := flags.Bool("yes", false, "skip the confirmation prompt")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(stderr, cliUsage)
+		return 2
+	}
+	id := flags.Arg(0)
+
+	model := NewModel(&core.State{}, resolver)
+	if model.err != "" {
+		fmt.Fprintln(stderr, "glyph: config:", model.err)
+	}
+	command, ok := model.findCommandByID(id)
+	if !ok {
+		fmt.Fprintln(stderr, "glyph: command not found:", id)
+		return 2
+	}
+
+	if *dryRun {
+		inv := model.resolveInvocation(command)
+		printInvocation(stdout, inv)
+		if inv.failed() {
+			return 1
+		}
+		return 0
+	}
+
+	if command.Confirm != core.ConfirmNone && !*yes {
+		if !confirmOnTerminal(command, os.Stdin, stderr) {
+			fmt.Fprintln(stderr, "glyph: canceled")
+			return 1
+		}
+	}
+	return model.runAttached(command, *yes, stderr)
+}
+
+func keysCLI(args []string, resolver core.WorkspaceResolver, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("keys", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dropped := flags.Bool("dropped", false, "only list bindings that are not in effect")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		fmt.Fprint(stderr, cliUsage)
+		return 2
+	}
+
+	model := NewModel(&core.State{}, resolver)
+	printBindings(stdout, model.bindingRecords(), *dropped)
+	return 0
+}
+
+func (inv invocation) failed() bool {
+	if inv.err != nil {
+		return true
+	}
+	for _, step := range inv.steps {
+		if step.failed() {
+			return true
+		}
+	}
+	return false
+}
+
+func printInvocation(out io.Writer, inv invocation) {
+	fmt.Fprintln(out, inv.command.Label)
+	for _, field := range inv.fields() {
+		fmt.Fprintf(out, "  %-8s %s\n", field.label, field.value)
+	}
+
+	scripts := []core.Command{inv.command}
+	for _, step := range inv.steps {
+		scripts = append(scripts, step.command)
+	}
+	for _, command := range scripts {
+		lines := scriptLines(command)
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n--- %s\n", command.Script)
+		for _, line := range lines {
+			fmt.Fprintln(out, line)
+		}
+	}
+}
+
+func confirmOnTerminal(command core.Command, in io.Reader, out io.Writer) bool {
+	reader := bufio.NewReader(in)
+	if command.Confirm == core.ConfirmTypeName {
+		fmt.Fprintf(out, "Type %s to run %s: ", command.ID, command.Label)
+		answer, _ := reader.ReadString('\n')
+		return strings.TrimSpace(answer) == command.ID
+	}
+	fmt.Fprintf(out, "Run %s? [y/N] ", command.Label)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// runAttached runs command in the foreground with the terminal's stdio.
+// Background and capture modes have no meaning outside the palette, so every
+// command runs like a terminal command here. Chain steps with a confirm gate
+// ask before they run unless yes is set.
+func (m Model) runAttached(command core.Command, yes bool, stderr io.Writer) int {
+	if command.Kind != core.CommandChain {
+		return exitCode(m.runAttachedStep(command), command, stderr)
+	}
+
+	failed := 0
+	for i, step := range command.Steps {
+		resolved, err := m.resolveChainStep(command, step)
+		if err != nil {
+			fmt.Fprintf(stderr, "glyph: %s step %d: %s\n", command.Label, i+1, err)
+			return 1
+		}
+		if resolved.Confirm != core.ConfirmNone && !yes {
+			if !confirmOnTerminal(resolved, os.Stdin, stderr) {
+				fmt.Fprintln(stderr, "glyph: canceled")
+				return 1
+			}
+		}
+		code := exitCode(m.runAttachedStep(resolved), resolved, stderr)
+		if code == 0 {
+			continue
+		}
+		failed = code
+		if command.StopOnError && !step.ContinueOnError {
+			return code
+		}
+	}
+	return failed
+}
+
+func (m Model) runAttachedStep(command core.Command) error {
+	if command.Kind != core.CommandExec {
+		return errors.New("only runnable from the palette")
+	}
+	process, err := shellExecCommand(command, m.startDir)
+	if err != nil {
+		return err
+	}
+	process.Stdin = os.Stdin
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+	runner := &execProcess{cmd: process, timeout: command.Timeout}
+	return runner.runWithTimeout()
+}
+
+func exitCode(err error, command core.Command, stderr io.Writer) int {
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintf(stderr, "glyph: %s failed: %s\n", command.Label, formatCommandExecError(err))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
@@ -2,6 +2,7 @@ package shell
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Noudea/glyph/internal/core"
@@ -14,19 +15,59 @@ const (
 	commandSourceSpellbook = "spellbook"
 )
 
+// Command groups; config commands may also name their own.
+const (
+	groupCommands  = "commands"
+	groupSpellbook = "spellbook"
+	groupSystem    = "system"
+	groupImports   = "imports"
+	groupDetected  = "detected"
+	groupAdhoc     = "adhoc"
+)
+
 func (m Model) launcherCommands() []core.Command {
-	out := make([]core.Command, 0, len(m.state.Commands)+1)
+	out := make([]core.Command, 0, len(m.state.Commands)+2)
 
 	// Add synthetic marketplace command.
 	out = append(out, core.Command{
 		ID:      commandMarketplaceOpen,
 		Label:   "Spellbook Marketplace",
 		Kind:    core.CommandAction,
-		Group:   "system",
+		Group:   groupSystem,
 		Source:  commandSourceManaged,
 		Managed: true,
 	})
 
+	out = append(out, core.Command{
+		ID:      commandJobsOpen,
+		Label:   jobsLabel(m.runningJobCount()),
+		Kind:    core.CommandAction,
+		Group:   groupSystem,
+		Source:  commandSourceManaged,
+		Managed: true,
+	})
+
+	out = append(out, core.Command{
+		ID:      commandSettingsOpen,
+		Label:   "Settings: Commands & Shortcuts",
+		Kind:    core.CommandAction,
+		Group:   groupSystem,
+		Source:  commandSourceManaged,
+		Managed: true,
+	})
+
+	out = append(out, core.Command{
+		ID:      commandKeysOpen,
+		Label:   "Keymap Inspector",
+		Kind:    core.CommandAction,
+		Group:   groupSystem,
+		Source:  commandSourceManaged,
+		Managed: true,
+	})
+
+	out = append(out, m.appCommands()...)
+	out = append(out, m.appActionCommands()...)
+
 	if m.state == nil || len(m.state.Commands) == 0 {
 		return out
 	}
@@ -48,3 +89,10 @@ func (m Model) launcherCommands() []core.Command {
 
 	return out
 }
+
+func jobsLabel(running int) string {
+	if running == 0 {
+		return "Background Jobs"
+	}
+	return "Background Jobs (" + strconv.Itoa(running) + " running)"
+}
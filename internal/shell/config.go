@@ -3,10 +3,12 @@ package shell
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Noudea/glyph/internal/core"
 	"github.com/Noudea/glyph/internal/marketplace"
@@ -16,14 +18,86 @@ type configFile struct {
 	Version   int                        `json:"version,omitempty"`
 	Commands  []commandConfig            `json:"commands"`
 	Shortcuts map[string]json.RawMessage `json:"shortcuts"`
+	// Detect toggles project detection; the project config wins when set.
+	Detect *bool `json:"detect,omitempty"`
+	// Imports lists task runner files to read commands from. Only the
+	// project config's imports are used.
+	Imports []importConfig `json:"imports,omitempty"`
+	// Leader is the key that "leader" stands for in shortcuts; the project
+	// config wins when set.
+	Leader string `json:"leader,omitempty"`
+	// Keys rebinds mode actions by ID, e.g. "jobs.stop"; project entries
+	// replace global ones.
+	Keys map[string]stringList `json:"keys,omitempty"`
 }
 
 type commandConfig struct {
-	ID      string `json:"id"`
-	Label   string `json:"label"`
-	Run     string `json:"run,omitempty"`
-	Script  string `json:"script,omitempty"`
-	Enabled *bool  `json:"enabled,omitempty"`
+	ID      string            `json:"id"`
+	Label   string            `json:"label"`
+	Run     string            `json:"run,omitempty"`
+	Script  string            `json:"script,omitempty"`
+	Argv    []string          `json:"argv,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	EnvFile stringList        `json:"envFile,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	Shell   string            `json:"shell,omitempty"`
+	After   *afterConfig      `json:"after,omitempty"`
+	When    *whenConfig       `json:"when,omitempty"`
+	Group   string            `json:"group,omitempty"`
+	Tags    stringList        `json:"tags,omitempty"`
+
+	Background bool   `json:"background,omitempty"`
+	Capture    bool   `json:"capture,omitempty"`
+	Timeout    string `json:"timeout,omitempty"`
+
+	Confirm json.RawMessage `json:"confirm,omitempty"`
+	Danger  string          `json:"danger,omitempty"`
+
+	Steps       []stepConfig      `json:"steps,omitempty"`
+	StopOnError *bool             `json:"stopOnError,omitempty"`
+	Args        map[string]string `json:"args,omitempty"`
+}
+
+// stepConfig is a chain step. A bare string references another command ID;
+// an object either references one via "command" or defines an inline step.
+type stepConfig struct {
+	commandConfig
+	Command         string `json:"command,omitempty"`
+	ContinueOnError bool   `json:"continueOnError,omitempty"`
+}
+
+func (s *stepConfig) UnmarshalJSON(data []byte) error {
+	var ref string
+	if err := json.Unmarshal(data, &ref); err == nil {
+		*s = stepConfig{Command: ref}
+		return nil
+	}
+	type plain stepConfig
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+type afterConfig struct {
+	Pause  string `json:"pause,omitempty"`
+	Return string `json:"return,omitempty"`
+	Clear  *bool  `json:"clear,omitempty"`
+}
+
+// stringList accepts either a single JSON string or an array of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = list
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err != nil {
+		return errors.New("expected a string or a list of strings")
+	}
+	*l = stringList{single}
+	return nil
 }
 
 type configWriteFile struct {
@@ -113,36 +187,49 @@ func decodeShortcutMap(input map[string]json.RawMessage) (map[string][]string, [
 	out := make(map[string][]string, len(input))
 	var errs []error
 	for commandID, raw := range input {
-		var list []string
-		if err := json.Unmarshal(raw, &list); err == nil {
-			out[commandID] = list
-			continue
-		}
-		var single string
-		if err := json.Unmarshal(raw, &single); err == nil {
-			out[commandID] = []string{single}
+		keys, err := decodeShortcuts(raw)
+		if err != nil {
+			errs = append(errs, errors.New("invalid shortcut format for "+commandID))
 			continue
 		}
-		errs = append(errs, errors.New("invalid shortcut format for "+commandID))
+		out[commandID] = keys
 	}
 	return out, errs
 }
 
-func mergeCommands(global []commandConfig, globalRoot string, project []commandConfig, projectRoot string) ([]core.Command, []error) {
+// decodeShortcuts reads a shortcut value: a key or a list of keys.
+func decodeShortcuts(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var keys stringList
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func mergeCommands(global []commandConfig, globalRoot string, globalPath string, project []commandConfig, projectRoot string, projectPath string, workDir string, when *whenContext) ([]core.Command, []error) {
 	commandsByID := make(map[string]core.Command)
 	order := make([]string, 0, len(global)+len(project))
 	orderSet := make(map[string]struct{})
 	var errs []error
 
-	apply := func(entries []commandConfig, source string, configRoot string) {
+	apply := func(entries []commandConfig, source string, configRoot string, path string) {
 		for _, item := range entries {
-			command, ok, err := parseCommandConfig(item, source, configRoot)
+			// A project entry whose conditions fail hides the global one too.
+			if !when.matches(item.When) {
+				delete(commandsByID, strings.TrimSpace(item.ID))
+				continue
+			}
+			command, ok, err := parseCommandConfig(item, source, configRoot, workDir)
 			if err != nil {
 				errs = append(errs, err)
 			}
 			if !ok {
 				continue
 			}
+			command.SourceFile = path
 			if _, exists := orderSet[command.ID]; !exists {
 				orderSet[command.ID] = struct{}{}
 				order = append(order, command.ID)
@@ -151,8 +238,8 @@ func mergeCommands(global []commandConfig, globalRoot string, project []commandC
 		}
 	}
 
-	apply(global, commandSourceGlobal, globalRoot)
-	apply(project, commandSourceProject, projectRoot)
+	apply(global, commandSourceGlobal, globalRoot, globalPath)
+	apply(project, commandSourceProject, projectRoot, projectPath)
 
 	out := make([]core.Command, 0, len(order))
 	for _, id := range order {
@@ -173,7 +260,10 @@ func mergeCommands(global []commandConfig, globalRoot string, project []commandC
 	return out, errs
 }
 
-func parseCommandConfig(item commandConfig, source string, configRoot string) (core.Command, bool, error) {
+// parseCommandConfig validates a config entry and resolves its paths.
+// configRoot is the directory holding the config file and workDir is the
+// project root used for relative cwd values.
+func parseCommandConfig(item commandConfig, source string, configRoot string, workDir string) (core.Command, bool, error) {
 	id := strings.TrimSpace(item.ID)
 	if id == "" {
 		return core.Command{}, false, errors.New("command id is required")
@@ -182,16 +272,32 @@ func parseCommandConfig(item commandConfig, source string, configRoot string) (c
 		return core.Command{}, false, nil
 	}
 
+	if len(item.Steps) > 0 {
+		return parseChainConfig(item, source, configRoot, workDir)
+	}
+
 	run := strings.TrimSpace(item.Run)
 	script := strings.TrimSpace(item.Script)
 
-	if run == "" && script == "" {
-		return core.Command{}, false, errors.New("command run or script is required for " + id)
+	argv := item.Argv
+	if len(argv) > 0 {
+		if run != "" || script != "" {
+			return core.Command{}, false, errors.New("command argv cannot be combined with run or script for " + id)
+		}
+		if strings.TrimSpace(argv[0]) == "" {
+			return core.Command{}, false, errors.New("command argv needs a program name for " + id)
+		}
+		argv = append([]string(nil), argv...)
+	}
+
+	if run == "" && script == "" && len(argv) == 0 {
+		return core.Command{}, false, errors.New("command run, script or argv is required for " + id)
 	}
 
 	// script is resolved to an absolute path relative to configRoot.
 	if script != "" {
 		run = filepath.Join(configRoot, script)
+		script = run
 	}
 
 	label := strings.TrimSpace(item.Label)
@@ -199,17 +305,279 @@ func parseCommandConfig(item commandConfig, source string, configRoot string) (c
 		label = id
 	}
 
+	shell := strings.ToLower(strings.TrimSpace(item.Shell))
+	if !isSupportedShell(shell) {
+		return core.Command{}, false, errors.New("unsupported shell " + shell + " for " + id)
+	}
+
+	after, err := parseAfterConfig(item.After)
+	if err != nil {
+		return core.Command{}, false, errors.New(err.Error() + " for " + id)
+	}
+
+	runMode := core.RunTerminal
+	switch {
+	case item.Background && item.Capture:
+		return core.Command{}, false, errors.New("command cannot be both background and capture for " + id)
+	case item.Background:
+		runMode = core.RunBackground
+	case item.Capture:
+		runMode = core.RunCapture
+	}
+
+	danger, confirm, err := parseSafetyConfig(item.Danger, item.Confirm)
+	if err != nil {
+		return core.Command{}, false, errors.New(err.Error() + " for " + id)
+	}
+
+	var timeout time.Duration
+	if raw := strings.TrimSpace(item.Timeout); raw != "" {
+		timeout, err = time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return core.Command{}, false, errors.New("invalid timeout " + raw + " for " + id)
+		}
+	}
+
+	dir := ""
+	if cwd := strings.TrimSpace(item.Cwd); cwd != "" {
+		dir = resolveCommandDir(cwd, configRoot, workDir)
+	}
+
+	return core.Command{
+		ID:       id,
+		Label:    label,
+		Kind:     core.CommandExec,
+		Group:    commandGroup(item.Group),
+		Run:      run,
+		Script:   script,
+		Argv:     argv,
+		Source:   source,
+		Managed:  source == commandSourceManaged,
+		Shell:    shell,
+		Dir:      dir,
+		Env:      item.Env,
+		EnvFiles: resolveEnvFiles(item.EnvFile, configRoot),
+		After:    after,
+		RunMode:  runMode,
+		Timeout:  timeout,
+		Confirm:  confirm,
+		Danger:   danger,
+		Tags:     normalizeTags(item.Tags),
+	}, true, nil
+}
+
+// parseChainConfig parses a command made of steps. Inline steps inherit the
+// chain's cwd, shell, env and env files unless they set their own.
+func parseChainConfig(item commandConfig, source string, configRoot string, workDir string) (core.Command, bool, error) {
+	id := strings.TrimSpace(item.ID)
+	if item.Run != "" || item.Script != "" || len(item.Argv) > 0 {
+		return core.Command{}, false, errors.New("command steps cannot be combined with run, script or argv for " + id)
+	}
+	after, err := parseAfterConfig(item.After)
+	if err != nil {
+		return core.Command{}, false, errors.New(err.Error() + " for " + id)
+	}
+
+	steps := make([]core.ChainStep, 0, len(item.Steps))
+	for i, step := range item.Steps {
+		if ref := strings.TrimSpace(step.Command); ref != "" {
+			if ref == id {
+				return core.Command{}, false, errors.New("command " + id + " cannot run itself as a step")
+			}
+			steps = append(steps, core.ChainStep{Ref: ref, ContinueOnError: step.ContinueOnError})
+			continue
+		}
+		if len(step.Steps) > 0 {
+			return core.Command{}, false, fmt.Errorf("step %d of %s cannot have nested steps", i+1, id)
+		}
+
+		inline := step.commandConfig
+		inline.ID = fmt.Sprintf("%s#%d", id, i+1)
+		inline.Enabled = nil
+		if strings.TrimSpace(inline.Label) == "" {
+			inline.Label = fmt.Sprintf("Step %d", i+1)
+		}
+		if inline.Cwd == "" {
+			inline.Cwd = item.Cwd
+		}
+		if inline.Shell == "" {
+			inline.Shell = item.Shell
+		}
+		if len(inline.EnvFile) == 0 {
+			inline.EnvFile = item.EnvFile
+		}
+		inline.Env = mergeEnvMaps(item.Env, inline.Env)
+
+		command, _, err := parseCommandConfig(inline, source, configRoot, workDir)
+		if err != nil {
+			return core.Command{}, false, fmt.Errorf("step %d of %s: %w", i+1, id, err)
+		}
+		steps = append(steps, core.ChainStep{Inline: command, ContinueOnError: step.ContinueOnError})
+	}
+
+	label := strings.TrimSpace(item.Label)
+	if label == "" {
+		label = id
+	}
+
+	danger, confirm, err := parseSafetyConfig(item.Danger, item.Confirm)
+	if err != nil {
+		return core.Command{}, false, errors.New(err.Error() + " for " + id)
+	}
+
 	return core.Command{
-		ID:      id,
-		Label:   label,
-		Kind:    core.CommandExec,
-		Group:   "commands",
-		Run:     run,
-		Source:  source,
-		Managed: source == commandSourceManaged,
+		ID:          id,
+		Label:       label,
+		Kind:        core.CommandChain,
+		Group:       commandGroup(item.Group),
+		Source:      source,
+		Managed:     source == commandSourceManaged,
+		After:       after,
+		Steps:       steps,
+		StopOnError: item.StopOnError == nil || *item.StopOnError,
+		Args:        item.Args,
+		Env:         item.Env,
+		EnvFiles:    resolveEnvFiles(item.EnvFile, configRoot),
+		Confirm:     confirm,
+		Danger:      danger,
+		Tags:        normalizeTags(item.Tags),
 	}, true, nil
 }
 
+// resolveEnvFiles makes env file paths absolute relative to configRoot.
+func resolveEnvFiles(files stringList, configRoot string) []string {
+	var out []string
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(configRoot, file)
+		}
+		out = append(out, file)
+	}
+	return out
+}
+
+// mergeEnvMaps overlays maps in order; later maps win.
+func mergeEnvMaps(maps ...map[string]string) map[string]string {
+	var out map[string]string
+	for _, values := range maps {
+		for key, value := range values {
+			if out == nil {
+				out = make(map[string]string)
+			}
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// parseSafetyConfig reads the danger level and confirm setting. confirm is
+// true/false or "yes"/"type"; when omitted, medium danger asks yes/no and
+// high danger asks for the command ID to be typed.
+func parseSafetyConfig(rawDanger string, rawConfirm json.RawMessage) (core.DangerLevel, core.ConfirmKind, error) {
+	danger := core.DangerLevel(strings.ToLower(strings.TrimSpace(rawDanger)))
+	switch danger {
+	case core.DangerNone, core.DangerLow, core.DangerMedium, core.DangerHigh:
+	default:
+		return "", "", errors.New("invalid danger " + rawDanger)
+	}
+
+	if len(rawConfirm) == 0 {
+		switch danger {
+		case core.DangerHigh:
+			return danger, core.ConfirmTypeName, nil
+		case core.DangerMedium:
+			return danger, core.ConfirmYesNo, nil
+		}
+		return danger, core.ConfirmNone, nil
+	}
+
+	var flag bool
+	if err := json.Unmarshal(rawConfirm, &flag); err == nil {
+		if flag {
+			return danger, core.ConfirmYesNo, nil
+		}
+		return danger, core.ConfirmNone, nil
+	}
+	var kind string
+	if err := json.Unmarshal(rawConfirm, &kind); err != nil {
+		return "", "", errors.New("confirm must be true, false, \"yes\" or \"type\"")
+	}
+	switch confirm := core.ConfirmKind(strings.ToLower(strings.TrimSpace(kind))); confirm {
+	case core.ConfirmYesNo, core.ConfirmTypeName:
+		return danger, confirm, nil
+	default:
+		return "", "", errors.New("invalid confirm " + kind)
+	}
+}
+
+func parseAfterConfig(item *afterConfig) (core.AfterRun, error) {
+	var after core.AfterRun
+	if item == nil {
+		return after, nil
+	}
+	switch pause := core.PausePolicy(strings.ToLower(strings.TrimSpace(item.Pause))); pause {
+	case "":
+	case core.PauseAuto, core.PauseAlways, core.PauseNever, core.PauseOnFailure:
+		after.Pause = pause
+	default:
+		return after, errors.New("invalid after.pause " + string(pause))
+	}
+	switch target := core.ReturnTarget(strings.ToLower(strings.TrimSpace(item.Return))); target {
+	case "":
+	case core.ReturnPalette, core.ReturnMain, core.ReturnQuit:
+		after.Return = target
+	default:
+		return after, errors.New("invalid after.return " + string(target))
+	}
+	if item.Clear != nil {
+		after.KeepScreen = !*item.Clear
+	}
+	return after, nil
+}
+
+// resolveCommandDir expands ${GLYPH_CONFIG_DIR}, ${GLYPH_PROJECT_ROOT} and
+// environment variables in cwd, then anchors relative paths at workDir.
+func resolveCommandDir(cwd string, configRoot string, workDir string) string {
+	expanded := os.Expand(cwd, func(name string) string {
+		switch name {
+		case "GLYPH_CONFIG_DIR":
+			return configRoot
+		case "GLYPH_PROJECT_ROOT":
+			return workDir
+		}
+		return os.Getenv(name)
+	})
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(workDir, expanded)
+	}
+	return filepath.Clean(expanded)
+}
+
+// normalizeTags lowercases tags and drops empty ones and a leading "#".
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// commandGroup returns the launcher group for a config command, which
+// defaults to "commands".
+func commandGroup(group string) string {
+	if group = strings.TrimSpace(group); group != "" {
+		return group
+	}
+	return groupCommands
+}
+
 func commandEnabled(flag *bool) bool {
 	if flag == nil {
 		return true
@@ -225,14 +593,35 @@ func normalizeShortcutMap(input map[string][]string) map[string][]string {
 	return out
 }
 
-func loadSpellbookCommands(root string, source string, absolute bool) ([]core.Command, error) {
+func loadSpellbookCommands(root string, source string, absolute bool, when *whenContext) ([]core.Command, error) {
 	installed := marketplace.ListInstalled(root)
 	var commands []core.Command
+	var problems []error
 	for id, sb := range installed {
+		bookWhen, err := decodeWhen(sb.When)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("spellbook %s: invalid when: %w", id, err))
+			continue
+		}
+		if !when.matches(bookWhen) {
+			continue
+		}
+		bookName := strings.TrimSpace(sb.Name)
+		if bookName == "" {
+			bookName = id
+		}
 		for _, cmd := range sb.Commands {
 			if cmd.Enabled != nil && !*cmd.Enabled {
 				continue
 			}
+			cmdWhen, err := decodeWhen(cmd.When)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("spellbook %s: invalid when for %s: %w", id, cmd.ID, err))
+				continue
+			}
+			if !when.matches(cmdWhen) {
+				continue
+			}
 
 			// Resolve the script/run to an executable path.
 			// Spellbooks should use "script" (file relative to spellbook dir),
@@ -242,10 +631,11 @@ func loadSpellbookCommands(root string, source string, absolute bool) ([]core.Co
 				scriptFile = cmd.Run
 			}
 
-			var run string
+			var run, script string
 			if scriptFile != "" {
+				script = filepath.Join(root, "spellbooks", id, scriptFile)
 				if absolute {
-					run = filepath.Join(root, "spellbooks", id, scriptFile)
+					run = script
 				} else {
 					run = filepath.Join(".glyph", "spellbooks", id, scriptFile)
 				}
@@ -257,17 +647,40 @@ func loadSpellbookCommands(root string, source string, absolute bool) ([]core.Co
 			if label == "" {
 				label = cmd.ID
 			}
+			runMode := core.RunTerminal
+			if cmd.Capture {
+				runMode = core.RunCapture
+			}
+			danger, confirm, err := parseSafetyConfig(cmd.Danger, cmd.Confirm)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("spellbook %s: %w for %s", id, err, cmd.ID))
+				continue
+			}
+			shortcuts, err := decodeShortcuts(cmd.Shortcuts)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("spellbook %s: invalid shortcuts for %s", id, cmd.ID))
+			}
 			commands = append(commands, core.Command{
-				ID:     cmd.ID,
-				Label:  label,
-				Kind:   core.CommandExec,
-				Group:  "spellbook",
-				Run:    run,
-				Source: source,
+				ID:      cmd.ID,
+				Label:   label,
+				Kind:    core.CommandExec,
+				Group:   groupSpellbook,
+				Run:     run,
+				Source:  source,
+				RunMode: runMode,
+
+				Spellbook:  bookName,
+				SourceFile: filepath.Join(root, "spellbooks", id, "spellbook.json"),
+				Script:     script,
+				Confirm:    confirm,
+				Danger:     danger,
+				Tags:       normalizeTags(cmd.Tags),
+
+				SuggestedShortcuts: shortcuts,
 			})
 		}
 	}
-	return commands, nil
+	return commands, errors.Join(problems...)
 }
 
 func (m *Model) reloadConfig() error {
@@ -303,11 +716,13 @@ func (m *Model) reloadConfig() error {
 		Commands:  []commandConfig{},
 		Shortcuts: map[string]json.RawMessage{},
 	}
+	projectBroken := false
 	if found {
 		m.projectConfigPath = projectPath
 		projectLoaded, loadErr := loadConfig(projectPath)
 		if loadErr != nil {
 			problems = append(problems, loadErr)
+			projectBroken = true
 		} else {
 			projectConfig = projectLoaded
 		}
@@ -319,31 +734,111 @@ func (m *Model) reloadConfig() error {
 	if m.projectConfigPath != "" {
 		projectRoot = filepath.Dir(m.projectConfigPath) // .glyph/ directory
 	}
-	commands, commandProblems := mergeCommands(globalConfig.Commands, globalRoot.RootPath, projectConfig.Commands, projectRoot)
+	// Shortcuts of commands that are disabled or hidden stay in the file
+	// but are not applied.
+	inactive := make(map[string]struct{})
+	for _, item := range append(append([]commandConfig{}, globalConfig.Commands...), projectConfig.Commands...) {
+		inactive[strings.TrimSpace(item.ID)] = struct{}{}
+	}
+
+	// Conditions are evaluated once per load against the start folder.
+	projectDir := ""
+	if projectRoot != "" {
+		projectDir = filepath.Dir(projectRoot)
+	}
+	when := newWhenContext(m.startDir, projectDir)
+
+	commands, commandProblems := mergeCommands(globalConfig.Commands, globalRoot.RootPath, m.globalConfigPath, projectConfig.Commands, projectRoot, m.projectConfigPath, m.workDir(), when)
 	problems = append(problems, commandProblems...)
 
 	// Load spellbook commands from installed spellbooks.
-	globalSpellbookCmds, err := loadSpellbookCommands(globalRoot.RootPath, commandSourceSpellbook, true)
+	globalSpellbookCmds, err := loadSpellbookCommands(globalRoot.RootPath, commandSourceSpellbook, true, when)
 	if err != nil {
 		problems = append(problems, err)
 	}
 	commands = append(commands, globalSpellbookCmds...)
 
 	if projectRoot != "" {
-		projectSpellbookCmds, err := loadSpellbookCommands(projectRoot, commandSourceSpellbook, false)
+		projectSpellbookCmds, err := loadSpellbookCommands(projectRoot, commandSourceSpellbook, false, when)
 		if err != nil {
 			problems = append(problems, err)
 		}
 		commands = append(commands, projectSpellbookCmds...)
 	}
 
+	detectEnabled := commandEnabled(globalConfig.Detect)
+	if projectConfig.Detect != nil {
+		detectEnabled = *projectConfig.Detect
+	}
+	imported, importFiles, importProblems := importedCommands(projectConfig.Imports, m.workDir(), commands)
+	problems = append(problems, importProblems...)
+	commands = append(commands, imported...)
+	watched := importFiles
+	if projectBroken {
+		// A broken project config lists no imports; keep watching the last
+		// good set so fixing one of its files still reloads.
+		watched = make([]string, 0, len(m.importFiles))
+		for path := range m.importFiles {
+			watched = append(watched, path)
+		}
+	}
+	m.recordImportFiles(watched)
+
+	if detectEnabled {
+		commands = append(commands, detectedCommands(m.workDir(), commands, importFiles)...)
+	}
+
 	if m.state != nil {
 		m.state.Commands = commands
 	}
+	// The selected command may have changed under the cached preview.
+	m.launcherPreviewCache = nil
+	m.refreshLauncherPreview()
 
-	shortcutOverrides, shortcutProblems := decodeShortcutMap(globalConfig.Shortcuts)
+	m.leaderKey = normalizeShortcutKey(globalConfig.Leader)
+	if projectConfig.Leader != "" {
+		m.leaderKey = normalizeShortcutKey(projectConfig.Leader)
+	}
+	if strings.Contains(m.leaderKey, " ") {
+		problems = append(problems, errors.New("leader must be a single key: "+m.leaderKey))
+		m.leaderKey = ""
+	}
+
+	keyLayers := make([]bindingLayer, 0, 2)
+	for _, layer := range []struct {
+		origin string
+		keys   map[string]stringList
+	}{{originGlobal, globalConfig.Keys}, {originProject, projectConfig.Keys}} {
+		bindings := make(map[string][]string, len(layer.keys))
+		for id, list := range layer.keys {
+			bindings[id] = list
+		}
+		keyLayers = append(keyLayers, bindingLayer{origin: layer.origin, bindings: bindings})
+	}
+	if err := m.applyKeys(keyLayers); err != nil {
+		problems = append(problems, err)
+	}
+
+	// Project shortcuts replace the global binding of the same command.
+	globalShortcuts, shortcutProblems := decodeShortcutMap(globalConfig.Shortcuts)
+	problems = append(problems, shortcutProblems...)
+	projectShortcuts, shortcutProblems := decodeShortcutMap(projectConfig.Shortcuts)
 	problems = append(problems, shortcutProblems...)
-	if err := m.applyShortcuts(shortcutOverrides); err != nil {
+	for _, command := range commands {
+		delete(inactive, command.ID)
+	}
+	suggested := make(map[string][]string)
+	for _, command := range commands {
+		if command.Source == commandSourceSpellbook && len(command.SuggestedShortcuts) > 0 {
+			suggested[command.ID] = command.SuggestedShortcuts
+		}
+	}
+	shortcutLayers := []bindingLayer{
+		{origin: originSpellbook, bindings: suggested},
+		{origin: originGlobal, bindings: globalShortcuts},
+		{origin: originProject, bindings: projectShortcuts},
+	}
+	if err := m.applyShortcuts(shortcutLayers, inactive); err != nil {
 		problems = append(problems, err)
 	}
 
@@ -0,0 +1,459 @@
+package shell
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The helpers below edit config files by splicing text into them instead of
+// re-marshalling, so comments-free but hand-formatted files keep their
+// layout, key order and unknown keys.
+
+// addCommandToConfig appends entry to the commands list of the config file
+// at path, creating the file when it does not exist yet.
+func addCommandToConfig(path string, entry commandConfig) error {
+	data, err := readConfigForEdit(path)
+	if err != nil {
+		return err
+	}
+	start, end, err := rootObjectSpan(data)
+	if err != nil {
+		return err
+	}
+
+	listStart, listEnd, found, err := memberSpan(data, start, end, "commands")
+	if err != nil {
+		return err
+	}
+	if found {
+		if data[listStart] != '[' {
+			return errors.New(filepath.Base(path) + ": commands is not a list")
+		}
+		data = appendToContainer(data, listStart, listEnd, func(indent, unit string) string {
+			return marshalForEdit(entry, indent, unit)
+		})
+	} else {
+		data = appendToContainer(data, start, end, func(indent, unit string) string {
+			return `"commands": ` + marshalForEdit([]commandConfig{entry}, indent, unit)
+		})
+	}
+	return writeConfigEdit(path, data)
+}
+
+// setShortcutInConfig binds keys to commandID in the shortcuts object of the
+// config file at path, replacing an existing binding for commandID.
+func setShortcutInConfig(path string, commandID string, keys []string) error {
+	data, err := readConfigForEdit(path)
+	if err != nil {
+		return err
+	}
+	start, end, err := rootObjectSpan(data)
+	if err != nil {
+		return err
+	}
+	value := marshalForEdit(keys, "", "")
+
+	objStart, objEnd, found, err := memberSpan(data, start, end, "shortcuts")
+	if err != nil {
+		return err
+	}
+	if !found {
+		data = appendToContainer(data, start, end, func(indent, unit string) string {
+			return `"shortcuts": {` + "\n" + indent + unit + marshalForEdit(commandID, "", "") + ": " + value + "\n" + indent + "}"
+		})
+		return writeConfigEdit(path, data)
+	}
+	if data[objStart] != '{' {
+		return errors.New(filepath.Base(path) + ": shortcuts is not an object")
+	}
+	data, err = setObjectMember(data, objStart, objEnd, commandID, value)
+	if err != nil {
+		return err
+	}
+	return writeConfigEdit(path, data)
+}
+
+// removeShortcutInConfig drops the binding for commandID from the config
+// file at path, if there is one.
+func removeShortcutInConfig(path string, commandID string) error {
+	data, err := readConfigForEdit(path)
+	if err != nil {
+		return err
+	}
+	start, end, err := rootObjectSpan(data)
+	if err != nil {
+		return err
+	}
+	objStart, objEnd, found, err := memberSpan(data, start, end, "shortcuts")
+	if err != nil || !found || data[objStart] != '{' {
+		return err
+	}
+	data, err = removeObjectMember(data, objStart, objEnd, commandID)
+	if err != nil {
+		return err
+	}
+	return writeConfigEdit(path, data)
+}
+
+// configMember is one key to set on a command entry. A nil value removes
+// the key.
+type configMember struct {
+	key   string
+	value any
+}
+
+// updateCommandInConfig edits the index-th entry of the commands list in
+// place. Keys not mentioned in changes, known or not, are left untouched.
+func updateCommandInConfig(path string, index int, changes []configMember) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		_, _, spans, err := commandEntries(data)
+		if err != nil {
+			return err
+		}
+		if index < 0 || index >= len(spans) {
+			return errors.New("command entry no longer exists")
+		}
+		entry := spans[index]
+		if data[entry.start] != '{' {
+			return errors.New("command entry is not an object")
+		}
+		if change.value == nil {
+			data, err = removeObjectMember(data, entry.start, entry.end, change.key)
+		} else {
+			data, err = setObjectMember(data, entry.start, entry.end, change.key, marshalForEdit(change.value, "", ""))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writeConfigEdit(path, data)
+}
+
+// deleteCommandInConfig removes the index-th entry of the commands list.
+func deleteCommandInConfig(path string, index int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	start, end, spans, err := commandEntries(data)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(spans) {
+		return errors.New("command entry no longer exists")
+	}
+	return writeConfigEdit(path, removeFromContainer(data, start, end, spans, index))
+}
+
+// swapCommandsInConfig exchanges the text of two entries of the commands
+// list, which reorders them without touching their formatting.
+func swapCommandsInConfig(path string, i, j int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, _, spans, err := commandEntries(data)
+	if err != nil {
+		return err
+	}
+	if i < 0 || j < 0 || i >= len(spans) || j >= len(spans) {
+		return errors.New("command entry no longer exists")
+	}
+	if i > j {
+		i, j = j, i
+	}
+	first := string(data[spans[i].start:spans[i].end])
+	second := string(data[spans[j].start:spans[j].end])
+	data = spliceBytes(data, spans[j].start, spans[j].end, first)
+	data = spliceBytes(data, spans[i].start, spans[i].end, second)
+	return writeConfigEdit(path, data)
+}
+
+// commandEntries returns the span of the commands list and of each entry.
+func commandEntries(data []byte) (int, int, []jsonSpan, error) {
+	start, end, err := rootObjectSpan(data)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	listStart, listEnd, found, err := memberSpan(data, start, end, "commands")
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if !found || data[listStart] != '[' {
+		return 0, 0, nil, errors.New("config has no commands list")
+	}
+	spans, err := arrayElements(data, listStart, listEnd)
+	return listStart, listEnd, spans, err
+}
+
+func readConfigForEdit(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []byte("{\n  \"version\": 1\n}\n"), nil
+	}
+	return data, err
+}
+
+// writeConfigEdit refuses to write anything that no longer parses.
+func writeConfigEdit(path string, data []byte) error {
+	if !json.Valid(data) {
+		return errors.New(filepath.Base(path) + ": edit produced invalid JSON")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// rootObjectSpan returns the byte range of the top-level object.
+func rootObjectSpan(data []byte) (int, int, error) {
+	start := skipSpace(data, 0)
+	if start >= len(data) || data[start] != '{' {
+		return 0, 0, errors.New("config must be a JSON object")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data[start:]))
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return 0, 0, err
+	}
+	return start, start + int(dec.InputOffset()), nil
+}
+
+// jsonSpan is a byte range [start, end) of a config file.
+type jsonSpan struct {
+	start int
+	end   int
+}
+
+// objectMember locates one member of an object: the span from the key to
+// the end of the value, and the span of the value alone.
+type objectMember struct {
+	key   string
+	whole jsonSpan
+	value jsonSpan
+}
+
+// objectMembers lists the members of the object spanning data[start:end].
+func objectMembers(data []byte, start, end int) ([]objectMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data[start:end]))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var members []objectMember
+	for dec.More() {
+		keyStart := skipSeparators(data, start+int(dec.InputOffset()))
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		afterKey := start + int(dec.InputOffset())
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		valueStart := skipSpace(data, afterKey)
+		if valueStart < len(data) && data[valueStart] == ':' {
+			valueStart = skipSpace(data, valueStart+1)
+		}
+		valueEnd := start + int(dec.InputOffset())
+		name, _ := tok.(string)
+		members = append(members, objectMember{
+			key:   name,
+			whole: jsonSpan{start: keyStart, end: valueEnd},
+			value: jsonSpan{start: valueStart, end: valueEnd},
+		})
+	}
+	return members, nil
+}
+
+// memberSpan finds key in the object spanning data[start:end] and returns
+// the byte range of its value.
+func memberSpan(data []byte, start, end int, key string) (int, int, bool, error) {
+	members, err := objectMembers(data, start, end)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	for _, member := range members {
+		if member.key == key {
+			return member.value.start, member.value.end, true, nil
+		}
+	}
+	return 0, 0, false, nil
+}
+
+// arrayElements lists the spans of the elements of the array spanning
+// data[start:end].
+func arrayElements(data []byte, start, end int) ([]jsonSpan, error) {
+	dec := json.NewDecoder(bytes.NewReader(data[start:end]))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var spans []jsonSpan
+	for dec.More() {
+		from := skipSeparators(data, start+int(dec.InputOffset()))
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		spans = append(spans, jsonSpan{start: from, end: start + int(dec.InputOffset())})
+	}
+	return spans, nil
+}
+
+// removeFromContainer cuts spans[i] out of the container spanning
+// data[start:end] together with the comma that separates it.
+func removeFromContainer(data []byte, start, end int, spans []jsonSpan, i int) []byte {
+	switch {
+	case i > 0:
+		return spliceBytes(data, spans[i-1].end, spans[i].end, "")
+	case len(spans) > 1:
+		return spliceBytes(data, spans[0].start, spans[1].start, "")
+	default:
+		return spliceBytes(data, start+1, end-1, "")
+	}
+}
+
+// setObjectMember replaces the value of key in the object spanning
+// data[start:end], or appends the member when it is missing. value is
+// already encoded JSON.
+func setObjectMember(data []byte, start, end int, key string, value string) ([]byte, error) {
+	members, err := objectMembers(data, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.key == key {
+			return spliceBytes(data, member.value.start, member.value.end, value), nil
+		}
+	}
+	return appendToContainer(data, start, end, func(string, string) string {
+		return marshalForEdit(key, "", "") + ": " + value
+	}), nil
+}
+
+// removeObjectMember deletes key from the object spanning data[start:end].
+func removeObjectMember(data []byte, start, end int, key string) ([]byte, error) {
+	members, err := objectMembers(data, start, end)
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]jsonSpan, len(members))
+	for i, member := range members {
+		spans[i] = member.whole
+	}
+	for i, member := range members {
+		if member.key == key {
+			return removeFromContainer(data, start, end, spans, i), nil
+		}
+	}
+	return data, nil
+}
+
+// appendToContainer adds an element (or member) at the end of the array or
+// object spanning data[start:end], matching the indentation already used.
+func appendToContainer(data []byte, start, end int, element func(indent, unit string) string) []byte {
+	closing := end - 1
+	unit := detectIndentUnit(data)
+	baseIndent := lineIndent(data, start)
+	indent := childIndent(data[start+1 : closing])
+	if indent == "" {
+		indent = baseIndent + unit
+	}
+	text := element(indent, unit)
+
+	last := closing - 1
+	for last > start && isSpace(data[last]) {
+		last--
+	}
+	if last == start {
+		return spliceBytes(data, start+1, closing, "\n"+indent+text+"\n"+baseIndent)
+	}
+	// A container written on one line stays on one line.
+	if !bytes.ContainsRune(data[start:end], '\n') {
+		return spliceBytes(data, last+1, last+1, ", "+element("", ""))
+	}
+	return spliceBytes(data, last+1, last+1, ",\n"+indent+text)
+}
+
+func marshalForEdit(value any, indent, unit string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if unit != "" {
+		enc.SetIndent(indent, unit)
+	}
+	_ = enc.Encode(value)
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func spliceBytes(data []byte, start, end int, text string) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(text))
+	out = append(out, data[:start]...)
+	out = append(out, text...)
+	return append(out, data[end:]...)
+}
+
+// detectIndentUnit returns the indentation of the first indented line, or
+// two spaces for files without any.
+func detectIndentUnit(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+// childIndent returns the indentation of the first element that starts on
+// its own line inside a container body.
+func childIndent(body []byte) string {
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\n' {
+			continue
+		}
+		j := i + 1
+		for j < len(body) && (body[j] == ' ' || body[j] == '\t') {
+			j++
+		}
+		if j < len(body) && body[j] != '\n' && body[j] != '\r' {
+			return string(body[i+1 : j])
+		}
+	}
+	return ""
+}
+
+func lineIndent(data []byte, pos int) string {
+	lineStart := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := lineStart
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[lineStart:end])
+}
+
+func skipSpace(data []byte, pos int) int {
+	for pos < len(data) && isSpace(data[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func skipSeparators(data []byte, pos int) int {
+	for pos < len(data) && (isSpace(data[pos]) || data[pos] == ',') {
+		pos++
+	}
+	return pos
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
@@ -0,0 +1,108 @@
+package shell
+
+import (
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+	confirmview "github.com/Noudea/glyph/internal/view/confirm"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type confirmState struct {
+	command  core.Command
+	input    textinput.Model
+	mismatch bool
+	// chainStep is set when the prompt gates the running chain's current
+	// step rather than a launch from the palette.
+	chainStep bool
+}
+
+// requestConfirm holds command behind a confirmation prompt instead of
+// launching it.
+func (m *Model) requestConfirm(command core.Command) {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = command.ID
+	input.CharLimit = 128
+	input.Width = 32
+	if command.Confirm == core.ConfirmTypeName {
+		input.Focus()
+	}
+
+	m.launcherInput.Blur()
+	m.confirm = confirmState{command: command, input: input}
+	m.mode = ModeConfirm
+}
+
+func (m *Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	command := m.confirm.command
+	action := m.keyAction(scopeConfirm, msg.String())
+
+	if action == "confirm.cancel" {
+		m.cancelConfirm()
+		return m, nil
+	}
+
+	if command.Confirm == core.ConfirmTypeName {
+		if action == "confirm.submit" {
+			if strings.TrimSpace(m.confirm.input.Value()) != command.ID {
+				m.confirm.mismatch = true
+				return m, nil
+			}
+			return m, m.acceptConfirm()
+		}
+		var cmd tea.Cmd
+		m.confirm.input, cmd = m.confirm.input.Update(msg)
+		m.confirm.mismatch = false
+		return m, cmd
+	}
+
+	switch action {
+	case "confirm.yes":
+		return m, m.acceptConfirm()
+	case "confirm.no":
+		m.cancelConfirm()
+	}
+	return m, nil
+}
+
+func (m *Model) acceptConfirm() tea.Cmd {
+	command := m.confirm.command
+	chainStep := m.confirm.chainStep
+	m.confirm = confirmState{}
+	if chainStep {
+		m.mode = ModeChain
+		m.chain.steps[m.chain.current].confirmed = true
+		return m.runChainStep()
+	}
+	m.mode = ModeMain
+	return m.launchCommand(command)
+}
+
+func (m *Model) cancelConfirm() {
+	chainStep := m.confirm.chainStep
+	m.confirm = confirmState{}
+	if chainStep {
+		m.cancelChainStep()
+		return
+	}
+	m.openLauncher()
+}
+
+func (m *Model) confirmViewState(height int) confirmview.ViewState {
+	command := m.confirm.command
+	state := confirmview.ViewState{
+		Label:    command.Label,
+		ID:       command.ID,
+		Danger:   string(command.Danger),
+		TypeName: command.Confirm == core.ConfirmTypeName,
+		Mismatch: m.confirm.mismatch,
+		Width:    m.width,
+		Height:   height,
+	}
+	if state.TypeName {
+		state.InputView = m.confirm.input.View()
+	}
+	return state
+}
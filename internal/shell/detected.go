@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"slices"
+
+	"github.com/Noudea/glyph/internal/core"
+	"github.com/Noudea/glyph/internal/detect"
+)
+
+const commandSourceDetected = "detected"
+
+// detectedCommands turns the tasks detected in dir into palette commands.
+// Tasks never replace a command the user or a spellbook already defines, and
+// files listed in imports are left to the import.
+func detectedCommands(dir string, existing []core.Command, imported []string) []core.Command {
+	taken := make(map[string]struct{}, len(existing))
+	for _, command := range existing {
+		taken[command.ID] = struct{}{}
+	}
+
+	var out []core.Command
+	for _, task := range detect.Detect(dir) {
+		if slices.Contains(imported, task.File) {
+			continue
+		}
+		id := commandSourceDetected + "." + task.Runner + "." + task.Name
+		command := taskCommand(task, id, commandSourceDetected)
+		if _, exists := taken[command.ID]; exists {
+			continue
+		}
+		taken[command.ID] = struct{}{}
+		out = append(out, command)
+	}
+	return out
+}
+
+func taskCommand(task detect.Task, id string, source string) core.Command {
+	command := core.Command{
+		ID:     id,
+		Label:  task.Label,
+		Kind:   core.CommandExec,
+		Group:  groupDetected,
+		Run:    task.Run,
+		Dir:    task.Dir,
+		Source: source,
+
+		SourceFile:  task.File,
+		Description: task.Description,
+		Tags:        []string{task.Runner},
+
+		// Build and test output is the point of running these, so keep it
+		// on screen instead of relying on the quick-run heuristic.
+		After: core.AfterRun{Pause: core.PauseAlways},
+	}
+	switch {
+	case task.Background:
+		command.RunMode = core.RunBackground
+	case task.Capture:
+		command.RunMode = core.RunCapture
+	}
+	return command
+}
@@ -0,0 +1,121 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+)
+
+type envEntry struct {
+	key    string
+	value  string
+	expand bool
+}
+
+// commandEnvironment builds the process environment for command: the
+// inherited environment, then each env file in order, then the command's own
+// env entries. Values may reference earlier variables as ${VAR}.
+func commandEnvironment(command core.Command) ([]string, error) {
+	env := make(map[string]string)
+	for _, pair := range os.Environ() {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			continue
+		}
+		env[key] = value
+	}
+	lookup := func(name string) string {
+		return env[name]
+	}
+
+	for _, path := range command.EnvFiles {
+		entries, err := loadEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			value := entry.value
+			if entry.expand {
+				value = os.Expand(value, lookup)
+			}
+			env[entry.key] = value
+		}
+	}
+
+	keys := make([]string, 0, len(command.Env))
+	for key := range command.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env[key] = os.Expand(command.Env[key], lookup)
+	}
+
+	names := make([]string, 0, len(env))
+	for key := range env {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	out := make([]string, 0, len(names))
+	for _, key := range names {
+		out = append(out, key+"="+env[key])
+	}
+	return out, nil
+}
+
+// loadEnvFile parses a dotenv file. It supports comments, an optional
+// "export " prefix, and single (literal) or double quoted values.
+func loadEnvFile(path string) ([]envEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("env file not found: %s", path)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []envEntry
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNo)
+		}
+		entries = append(entries, parseEnvValue(key, strings.TrimSpace(raw)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func parseEnvValue(key, raw string) envEntry {
+	if raw != "" && (raw[0] == '\'' || raw[0] == '"') {
+		if end := strings.LastIndexByte(raw, raw[0]); end > 0 {
+			value := raw[1:end]
+			if raw[0] == '\'' {
+				return envEntry{key: key, value: value}
+			}
+			value = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(value)
+			return envEntry{key: key, value: value, expand: true}
+		}
+	}
+	if idx := strings.Index(raw, " #"); idx >= 0 {
+		raw = strings.TrimSpace(raw[:idx])
+	}
+	return envEntry{key: key, value: raw, expand: true}
+}
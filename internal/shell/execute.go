@@ -7,12 +7,19 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/Noudea/glyph/internal/core"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type commandFinishedMsg struct {
 	CommandID string
 	Err       error
+	// TimedOut and Canceled tell a stopped run apart from a non-zero exit.
+	TimedOut bool
+	Canceled bool
+	// Chain is set when the run was step Step of the chain CommandID.
+	Chain bool
+	Step  int
 }
 
 func (m *Model) executeCommand(commandID string) tea.Cmd {
@@ -22,39 +29,105 @@ func (m *Model) executeCommand(commandID string) tea.Cmd {
 		return nil
 	case commandMarketplaceOpen:
 		return m.openMarketplace()
+	case commandJobsOpen:
+		m.openJobs()
+		return nil
+	case commandSettingsOpen:
+		m.openSettings()
+		return nil
+	case commandKeysOpen:
+		m.openKeysInspector()
+		return nil
 	default:
+		if id, ok := m.appCommandModule(commandID); ok {
+			return m.openApp(id)
+		}
+		if action, ok := m.findAppAction(commandID); ok {
+			return m.runAppAction(action)
+		}
 		command, ok := m.findCommandByID(commandID)
 		if !ok {
 			m.err = "command not found: " + commandID
 			return nil
 		}
-		run := strings.TrimSpace(command.Run)
-		if run == "" {
-			m.err = "command has no run value: " + commandID
+		if command.Confirm != core.ConfirmNone {
+			m.requestConfirm(command)
 			return nil
 		}
+		return m.launchCommand(command)
+	}
+}
 
-		process := shellExecCommand(run, m.startDir)
-		return tea.ExecProcess(process, func(err error) tea.Msg {
-			return commandFinishedMsg{CommandID: commandID, Err: err}
-		})
+// launchCommand runs command according to its kind and run mode. Callers
+// are responsible for any confirmation gate.
+func (m *Model) launchCommand(command core.Command) tea.Cmd {
+	commandID := command.ID
+	if command.Kind == core.CommandChain {
+		return m.startChain(command)
+	}
+	switch command.RunMode {
+	case core.RunBackground:
+		return m.startBackgroundJob(command)
+	case core.RunCapture:
+		return m.startCapture(command)
+	}
+	if strings.TrimSpace(command.Run) == "" && len(command.Argv) == 0 {
+		m.err = "command has no run value: " + commandID
+		return nil
+	}
+
+	process, err := shellExecCommand(command, m.startDir)
+	if err != nil {
+		m.err = fmt.Sprintf("%s failed: %s", command.Label, err)
+		return nil
 	}
+	return tea.Exec(newExecProcess(process, command), func(err error) tea.Msg {
+		return newCommandFinishedMsg(commandID, err)
+	})
 }
 
-func (m *Model) handleCommandFinished(msg commandFinishedMsg) {
-	if msg.Err == nil {
-		m.err = ""
-		m.openLauncher()
-		return
+func newCommandFinishedMsg(commandID string, err error) commandFinishedMsg {
+	var timeout *commandTimeoutError
+	return commandFinishedMsg{
+		CommandID: commandID,
+		Err:       err,
+		TimedOut:  errors.As(err, &timeout),
+		Canceled:  errors.Is(err, errCommandCanceled),
 	}
+}
 
+func (m *Model) handleCommandFinished(msg commandFinishedMsg) tea.Cmd {
 	command, ok := m.findCommandByID(msg.CommandID)
 	label := msg.CommandID
 	if ok {
 		label = command.Label
 	}
-	m.err = fmt.Sprintf("%s failed: %s", label, formatCommandExecError(msg.Err))
-	m.openLauncher()
+
+	if msg.Err != nil {
+		switch {
+		case msg.TimedOut, msg.Canceled:
+			m.err = fmt.Sprintf("%s %s", label, formatCommandExecError(msg.Err))
+		default:
+			m.err = fmt.Sprintf("%s failed: %s", label, formatCommandExecError(msg.Err))
+		}
+	} else {
+		m.err = ""
+	}
+
+	// A failed run never quits, so its error stays visible.
+	switch command.After.Return {
+	case core.ReturnQuit:
+		if msg.Err == nil {
+			return m.quit()
+		}
+		m.openLauncher()
+	case core.ReturnMain:
+		m.launcherInput.Blur()
+		m.mode = ModeMain
+	default:
+		m.openLauncher()
+	}
+	return nil
 }
 
 func (m *Model) openLauncher() {
@@ -62,36 +135,140 @@ func (m *Model) openLauncher() {
 	m.launcherInput.SetValue("")
 	m.launcherInput.Focus()
 	m.clampLauncherCursor()
+	if row, ok := m.selectedLauncherRow(); !ok || row.header {
+		m.focusFirstCommand()
+	}
+	m.refreshLauncherPreview()
 }
 
-func shellExecCommand(run string, cwd string) *exec.Cmd {
-	var command *exec.Cmd
-	if runtime.GOOS == "windows" {
-		command = exec.Command("cmd", "/C", wrapWindowsQuickPauseCommand(run))
-	} else {
-		command = exec.Command("sh", "-lc", wrapPosixQuickPauseCommand(run))
-	}
-	command.Dir = cwd
-	return command
+const shellNone = "none"
+
+var supportedShells = map[string]struct{}{
+	"":        {},
+	"sh":      {},
+	"bash":    {},
+	"zsh":     {},
+	"fish":    {},
+	"pwsh":    {},
+	"cmd":     {},
+	shellNone: {},
 }
 
-func wrapPosixQuickPauseCommand(run string) string {
-	return "clear; __glyph_start=$(date +%s); " +
-		run +
-		"; __glyph_status=$?; __glyph_end=$(date +%s); " +
-		"if [ $((__glyph_end-__glyph_start)) -lt 2 ]; then " +
-		"printf '\\n[glyph] Press Enter to return...'; IFS= read -r _; clear; " +
-		"fi; exit $__glyph_status"
+func isSupportedShell(shell string) bool {
+	_, ok := supportedShells[shell]
+	return ok
 }
 
-func wrapWindowsQuickPauseCommand(run string) string {
-	return "cls & " + run + " & set __glyph_status=%errorlevel% & echo. & echo [glyph] Press Enter to return... & pause >nul & cls & exit /b %__glyph_status%"
+func isPosixShell(shell string) bool {
+	return shell == "sh" || shell == "bash" || shell == "zsh"
+}
+
+// shellExecCommand builds the process for command. defaultDir is used when
+// the command does not declare its own working directory.
+func shellExecCommand(command core.Command, defaultDir string) (*exec.Cmd, error) {
+	run := strings.TrimSpace(command.Run)
+
+	var process *exec.Cmd
+	switch {
+	case len(command.Argv) > 0:
+		process = exec.Command(command.Argv[0], command.Argv[1:]...)
+	case command.Shell == "":
+		if runtime.GOOS == "windows" {
+			process = exec.Command("cmd", "/C", run)
+		} else {
+			process = exec.Command("sh", "-lc", run)
+		}
+	case isPosixShell(command.Shell):
+		process = exec.Command(command.Shell, "-lc", run)
+	case command.Shell == "cmd":
+		process = exec.Command("cmd", "/C", run)
+	case command.Shell == "fish":
+		process = exec.Command("fish", "-l", "-c", run)
+	case command.Shell == "pwsh":
+		process = exec.Command("pwsh", "-NoLogo", "-Command", run)
+	case command.Shell == shellNone:
+		argv, err := splitCommandLine(run)
+		if err != nil {
+			return nil, err
+		}
+		if len(argv) == 0 {
+			return nil, errors.New("empty command line")
+		}
+		process = exec.Command(argv[0], argv[1:]...)
+	default:
+		return nil, fmt.Errorf("unsupported shell %q", command.Shell)
+	}
+
+	env, err := commandEnvironment(command)
+	if err != nil {
+		return nil, err
+	}
+	process.Env = env
+	process.Dir = defaultDir
+	if command.Dir != "" {
+		process.Dir = command.Dir
+	}
+	return process, nil
+}
+
+// splitCommandLine splits run into arguments, honoring single quotes, double
+// quotes and backslash escapes. No other shell syntax is interpreted.
+func splitCommandLine(run string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range run {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape in command line")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
 }
 
 func formatCommandExecError(err error) string {
 	if err == nil {
 		return ""
 	}
+	var timeout *commandTimeoutError
+	if errors.As(err, &timeout) {
+		return timeout.Error()
+	}
+	if errors.Is(err, errCommandCanceled) {
+		return "canceled"
+	}
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
 		code := exitErr.ExitCode()
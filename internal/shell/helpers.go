@@ -2,23 +2,25 @@ package shell
 
 import (
 	"path/filepath"
-	"strings"
 
 	"github.com/Noudea/glyph/internal/core"
 )
 
 func (m Model) filteredCommands() []core.Command {
 	commands := m.launcherCommands()
-	query := strings.TrimSpace(strings.ToLower(m.launcherInput.Value()))
-	if query == "" {
+	query := parseLauncherQuery(m.launcherInput.Value())
+	switch {
+	case query.help:
+		return nil
+	case query.adhoc:
+		return m.adhocCommands(query.command)
+	case query.empty():
 		return commands
 	}
 
 	out := make([]core.Command, 0, len(commands))
 	for _, command := range commands {
-		if strings.Contains(strings.ToLower(command.Label), query) ||
-			strings.Contains(strings.ToLower(command.ID), query) ||
-			strings.Contains(strings.ToLower(command.Shortcut), query) {
+		if query.matches(command) {
 			out = append(out, command)
 		}
 	}
@@ -37,6 +39,15 @@ func (m Model) findCommandByID(id string) (core.Command, bool) {
 	return core.Command{}, false
 }
 
+// workDir returns the project root when a project config is loaded and the
+// start folder otherwise.
+func (m Model) workDir() string {
+	if m.projectConfigPath != "" {
+		return filepath.Dir(filepath.Dir(m.projectConfigPath))
+	}
+	return filepath.Clean(m.startDir)
+}
+
 func (m Model) workspaceTitle() string {
 	abs := filepath.Clean(m.startDir)
 	if abs == "." || abs == string(filepath.Separator) {
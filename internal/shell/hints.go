@@ -1,18 +1,120 @@
 package shell
 
-import "strings"
+import (
+	"strings"
+	"time"
+
+	"github.com/Noudea/glyph/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noticeDuration is how long a notification stays in the hint bar.
+const noticeDuration = 5 * time.Second
+
+type noticeExpiredMsg struct {
+	seq int
+}
+
+// notify shows text in the hint bar until it expires or is replaced.
+func (m *Model) notify(text string) tea.Cmd {
+	m.notice = text
+	m.noticeSeq++
+	seq := m.noticeSeq
+	return tea.Tick(noticeDuration, func(time.Time) tea.Msg {
+		return noticeExpiredMsg{seq: seq}
+	})
+}
 
 func (m Model) hintText() string {
+	if len(m.chord) > 0 {
+		return m.chordHint()
+	}
+	text := m.modeHintText()
+	if m.notice != "" && m.mode != ModeSplash {
+		if text == "" {
+			return m.notice
+		}
+		return m.notice + " · " + text
+	}
+	return text
+}
+
+func (m Model) modeHintText() string {
 	switch m.mode {
+	case ModeSaveCommand:
+		return m.formHint()
+	case ModeSettings:
+		if m.settings.editing {
+			return m.formHint()
+		}
+		return m.settingsFooter()
 	case ModeLauncher:
-		return "type to filter · ↑/↓ move · enter run · esc/" + m.shortcutsHint(commandLauncherOpen, "ctrl+p/ctrl+k/alt+p") + " close"
+		// Failed and timed-out runs come back to the palette, so their
+		// error is shown here too.
+		errText := ""
+		if m.err != "" {
+			errText = "error: " + m.err
+		}
+		if query := parseLauncherQuery(m.launcherInput.Value()); query.adhoc {
+			return joinHints(errText, m.keyHints("launcher.run", "run", "launcher.save", "save as command", "launcher.close", "close"))
+		}
+		return joinHints(errText, "type to filter", m.keyHints(
+			"launcher.up|launcher.down", "move",
+			"launcher.run", "run",
+			"launcher.close", "close",
+			"global.help", "keys",
+		))
 	case ModeMarketplace:
-		return "↑/↓ navigate · tab scope · esc back"
+		if m.marketplace.confirmInstall != "" {
+			return m.keyHints("install.global", "global", "install.project", "project", "install.cancel", "cancel")
+		}
+		return m.keyHints("marketplace.up|marketplace.down", "navigate", "marketplace.back", "back", "global.help", "keys")
+	case ModeChain:
+		if !m.chain.done {
+			return "running " + m.chain.command.Label
+		}
+		hint := m.chainFooter()
+		if m.err != "" {
+			return "error: " + m.err + " · " + hint
+		}
+		return hint
+	case ModeConfirm:
+		if m.confirm.command.Confirm == core.ConfirmTypeName {
+			return joinHints("type the command id", m.keyHints("confirm.submit", "confirm", "confirm.cancel", "cancel"))
+		}
+		return m.keyHints("confirm.yes", "run", "confirm.no|confirm.cancel", "cancel")
+	case ModeOutput:
+		if m.capture.searching {
+			return joinHints("type to search", m.keyHints("search.find", "find", "search.cancel", "cancel"))
+		}
+		pairs := []string{"output.up|output.down", "scroll", "output.top|output.bottom", "top/bottom", "output.search", "search", "output.copy-line", "copy"}
+		if m.capture.running {
+			pairs = append(pairs, "output.cancel", "cancel")
+		} else {
+			pairs = append(pairs, "output.rerun", "re-run")
+		}
+		return m.keyHints(append(pairs, "output.back", "back")...)
+	case ModeKeys:
+		return m.keysFooter()
+	case ModeJobs:
+		return m.keyHints("jobs.up|jobs.down", "select", "jobs.stop", "stop", "jobs.restart", "restart", "jobs.remove", "remove", "jobs.back", "back")
 	case ModeMain:
 		hints := []string{
 			m.workspaceHint(),
 			m.shortcutsHint(commandLauncherOpen, "ctrl+p/ctrl+k/alt+p") + " command palette",
 		}
+		if module := m.activeModule(); module != nil {
+			hints = []string{
+				module.Hint(),
+				m.primaryShortcut(commandLauncherOpen, "ctrl+p") + " palette",
+			}
+			if apps := m.keyHints("main.next-app|main.prev-app", "switch app", "main.close-app", "close app"); apps != "" {
+				hints = append(hints, apps)
+			}
+		}
+		if help := m.keyHints("global.help", "keys"); help != "" {
+			hints = append(hints, help)
+		}
 		if m.err != "" {
 			hints = append([]string{"error: " + m.err}, hints...)
 		}
@@ -21,3 +123,61 @@ func (m Model) hintText() string {
 		return ""
 	}
 }
+
+func (m Model) formHint() string {
+	return m.keyHints("form.next", "next field", "form.submit", "save", "form.cancel", "cancel")
+}
+
+// joinHints joins non-empty hint parts.
+func joinHints(parts ...string) string {
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return strings.Join(out, " · ")
+}
+
+func (m Model) chainFooter() string {
+	return m.keyHints("chain.return", "return", "chain.rerun", "re-run")
+}
+
+func (m Model) outputFooter() string {
+	pairs := []string{
+		"output.search", "search",
+		"output.next-match|output.prev-match", "next/prev",
+		"output.copy-line", "copy line",
+		"output.copy-all", "copy all",
+	}
+	if m.capture.running {
+		pairs = append(pairs, "output.cancel", "cancel")
+	} else {
+		pairs = append(pairs, "output.rerun", "re-run")
+	}
+	return m.keyHints(append(pairs, "output.back", "back")...)
+}
+
+func (m Model) settingsFooter() string {
+	if m.settings.deleting {
+		return joinHints(m.keyHints("settings.confirm-delete", "delete"), "any other key cancels")
+	}
+	return m.keyHints(
+		"settings.new", "new",
+		"settings.edit", "edit",
+		"settings.toggle", "enable/disable",
+		"settings.delete", "delete",
+		"settings.move-up|settings.move-down", "reorder",
+		"settings.scope", "scope",
+		"settings.back", "back",
+	)
+}
+
+// firstKey returns the first key bound to an action for display.
+func (m Model) firstKey(id string) string {
+	keys := m.actionKeys(id)
+	if len(keys) == 0 {
+		return ""
+	}
+	return displayKey(keys[0])
+}
@@ -0,0 +1,55 @@
+package shell
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// maxHistory bounds the number of ad-hoc commands remembered.
+const maxHistory = 50
+
+func historyPath(globalRoot string) string {
+	return filepath.Join(globalRoot, "history", "adhoc.json")
+}
+
+// loadHistory reads the ad-hoc command history, most recent first. A
+// missing file is an empty history.
+func loadHistory(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func saveHistory(path string, entries []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data := marshalForEdit(entries, "", "  ") + "\n"
+	return os.WriteFile(path, []byte(data), 0o644)
+}
+
+// rememberAdhoc moves run to the front of the history and persists it.
+func (m *Model) rememberAdhoc(run string) error {
+	entries := []string{run}
+	for _, entry := range m.history {
+		if entry != run && len(entries) < maxHistory {
+			entries = append(entries, entry)
+		}
+	}
+	m.history = entries
+	if m.historyPath == "" {
+		return nil
+	}
+	return saveHistory(m.historyPath, entries)
+}
@@ -0,0 +1,190 @@
+package shell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Noudea/glyph/internal/core"
+	"github.com/Noudea/glyph/internal/detect"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// importPollInterval is how often imported task files are checked for
+// changes.
+const importPollInterval = 2 * time.Second
+
+// importConfig declares a task runner file to turn into commands. A bare
+// string is shorthand for {"path": ...}.
+type importConfig struct {
+	Path string `json:"path"`
+	// Type is make, just, npm or task; it is inferred from the file name
+	// when empty.
+	Type string `json:"type,omitempty"`
+	// Label prefixes the command labels, e.g. "Web" gives "Web: dev".
+	Label string `json:"label,omitempty"`
+	Group string `json:"group,omitempty"`
+	// ID prefixes the command IDs; it defaults to the file's folder and type.
+	ID string `json:"id,omitempty"`
+}
+
+func (i *importConfig) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*i = importConfig{Path: path}
+		return nil
+	}
+	type plain importConfig
+	return json.Unmarshal(data, (*plain)(i))
+}
+
+type importPollMsg struct{}
+
+func importPollCmd() tea.Cmd {
+	return tea.Tick(importPollInterval, func(time.Time) tea.Msg {
+		return importPollMsg{}
+	})
+}
+
+// importedCommands reads every import relative to root. It returns the
+// commands, the resolved file paths (so detection and the change poller can
+// use them) and any problems.
+func importedCommands(imports []importConfig, root string, existing []core.Command) ([]core.Command, []string, []error) {
+	taken := make(map[string]struct{}, len(existing))
+	for _, command := range existing {
+		taken[command.ID] = struct{}{}
+	}
+
+	var (
+		out   []core.Command
+		files []string
+		errs  []error
+	)
+	for _, item := range imports {
+		raw := strings.TrimSpace(item.Path)
+		if raw == "" {
+			errs = append(errs, errors.New("import path is required"))
+			continue
+		}
+		path := raw
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		files = append(files, path)
+
+		kind := strings.ToLower(strings.TrimSpace(item.Type))
+		if kind == "" {
+			kind = importType(path)
+		}
+		tasks, err := parseImport(kind, path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("import %s: %w", raw, err))
+			continue
+		}
+
+		prefix := strings.TrimSpace(item.ID)
+		if prefix == "" {
+			prefix = importIDPrefix(root, path, kind)
+		}
+		for _, task := range tasks {
+			command := taskCommand(task, prefix+"."+task.Name, commandSourceProject)
+			if label := strings.TrimSpace(item.Label); label != "" {
+				command.Label = label + ": " + task.Name
+			}
+			command.Group = groupImports
+			if group := strings.TrimSpace(item.Group); group != "" {
+				command.Group = group
+			}
+			if _, exists := taken[command.ID]; exists {
+				continue
+			}
+			taken[command.ID] = struct{}{}
+			out = append(out, command)
+		}
+	}
+	return out, files, errs
+}
+
+func importType(path string) string {
+	name := filepath.Base(path)
+	switch {
+	case name == "package.json":
+		return "npm"
+	case slices.Contains(detect.JustfileNames, name):
+		return "just"
+	case slices.Contains(detect.TaskfileNames, name):
+		return "task"
+	default:
+		return "make"
+	}
+}
+
+func parseImport(kind string, path string) ([]detect.Task, error) {
+	switch kind {
+	case "make":
+		return detect.MakeTargets(path)
+	case "just":
+		return detect.JustRecipes(path)
+	case "npm":
+		return detect.PackageScripts(path)
+	case "task":
+		return detect.TaskfileTasks(path)
+	default:
+		return nil, errors.New("unknown import type " + kind)
+	}
+}
+
+// importIDPrefix names imports after their folder, so web/package.json and
+// api/package.json do not collide: "npm", "web.npm", "api.npm".
+func importIDPrefix(root string, path string, kind string) string {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return kind
+	}
+	return strings.ReplaceAll(filepath.ToSlash(rel), "/", ".") + "." + kind
+}
+
+// recordImportFiles remembers the modification times the poller compares
+// against. Missing files are recorded too, so creating one triggers a reload.
+func (m *Model) recordImportFiles(files []string) {
+	m.importFiles = make(map[string]time.Time, len(files))
+	for _, path := range files {
+		m.importFiles[path] = fileModTime(path)
+	}
+}
+
+func (m *Model) handleImportPoll() tea.Cmd {
+	var changed []string
+	for path, seen := range m.importFiles {
+		if !fileModTime(path).Equal(seen) {
+			changed = append(changed, filepath.Base(path))
+		}
+	}
+	if len(changed) == 0 {
+		return importPollCmd()
+	}
+
+	slices.Sort(changed)
+	var notice tea.Cmd
+	if err := m.reloadConfig(); err != nil {
+		m.err = err.Error()
+	} else {
+		m.err = ""
+		notice = m.notify("Reloaded commands from " + strings.Join(changed, ", "))
+	}
+	m.clampLauncherCursor()
+	return tea.Batch(notice, importPollCmd())
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
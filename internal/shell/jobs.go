@@ -0,0 +1,263 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Noudea/glyph/internal/core"
+	jobsview "github.com/Noudea/glyph/internal/view/jobs"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const commandJobsOpen = "jobs.open"
+
+type jobStatus string
+
+const (
+	jobRunning jobStatus = "running"
+	jobExited  jobStatus = "exited"
+	jobFailed  jobStatus = "failed"
+	jobStopped jobStatus = "stopped"
+)
+
+type job struct {
+	id      int
+	command core.Command
+	process *trackedProcess
+	output  *outputBuffer
+	status  jobStatus
+	err     error
+	started time.Time
+	ended   time.Time
+	restart bool
+}
+
+type jobsState struct {
+	jobs   []*job
+	cursor int
+	nextID int
+}
+
+type jobExitedMsg struct {
+	id  int
+	err error
+}
+
+func (m *Model) startBackgroundJob(command core.Command) tea.Cmd {
+	m.jobs.nextID++
+	j := &job{id: m.jobs.nextID, command: command}
+	if err := m.spawnJob(j); err != nil {
+		m.err = fmt.Sprintf("%s failed: %s", command.Label, err)
+		return nil
+	}
+	m.jobs.jobs = append(m.jobs.jobs, j)
+	m.err = ""
+	return m.notify(command.Label + " started in background")
+}
+
+func (m *Model) spawnJob(j *job) error {
+	process, err := shellExecCommand(j.command, m.startDir)
+	if err != nil {
+		j.status = jobFailed
+		j.err = err
+		return err
+	}
+
+	events := m.events
+	if j.output == nil {
+		j.output = newOutputBuffer(m.outputNotifier())
+	} else {
+		j.output.Reset()
+	}
+	process.Stdout = j.output
+	process.Stderr = j.output
+
+	id := j.id
+	tracked, err := startTrackedProcess(process, j.command.Timeout, func(err error) {
+		events <- jobExitedMsg{id: id, err: err}
+	})
+	if err != nil {
+		j.status = jobFailed
+		j.err = err
+		return err
+	}
+	j.process = tracked
+	j.status = jobRunning
+	j.err = nil
+	j.restart = false
+	j.started = time.Now()
+	j.ended = time.Time{}
+	return nil
+}
+
+func (m *Model) handleJobExited(msg jobExitedMsg) tea.Cmd {
+	j := m.findJob(msg.id)
+	if j == nil {
+		return nil
+	}
+	j.ended = time.Now()
+	j.err = msg.err
+	if j.restart {
+		if err := m.spawnJob(j); err != nil {
+			return m.notify(j.command.Label + " failed to restart: " + err.Error())
+		}
+		return m.notify(j.command.Label + " restarted")
+	}
+	switch {
+	case errors.Is(msg.err, errCommandCanceled):
+		j.status = jobStopped
+		return m.notify(j.command.Label + " stopped")
+	case msg.err != nil:
+		j.status = jobFailed
+		return m.notify(j.command.Label + " exited: " + formatCommandExecError(msg.err))
+	default:
+		j.status = jobExited
+		return m.notify(j.command.Label + " finished")
+	}
+}
+
+func (m *Model) findJob(id int) *job {
+	for _, j := range m.jobs.jobs {
+		if j.id == id {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m *Model) selectedJob() *job {
+	if m.jobs.cursor < 0 || m.jobs.cursor >= len(m.jobs.jobs) {
+		return nil
+	}
+	return m.jobs.jobs[m.jobs.cursor]
+}
+
+func (m *Model) stopJob(j *job) {
+	if j == nil || j.status != jobRunning || j.process == nil {
+		return
+	}
+	j.process.Cancel()
+}
+
+func (m *Model) restartJob(j *job) tea.Cmd {
+	if j == nil {
+		return nil
+	}
+	if j.status == jobRunning {
+		// Relaunch from the exit event so the old process is fully gone and
+		// its exit is not attributed to the new run.
+		m.stopJob(j)
+		j.restart = true
+		return nil
+	}
+	if err := m.spawnJob(j); err != nil {
+		return m.notify(j.command.Label + " failed to restart: " + err.Error())
+	}
+	return m.notify(j.command.Label + " restarted")
+}
+
+// stopAllProcesses stops the jobs, the running capture and the processes
+// modules started, and waits for them so none outlives Glyph.
+func (m *Model) stopAllProcesses() {
+	var processes []*trackedProcess
+	for _, j := range m.jobs.jobs {
+		if j.status == jobRunning && j.process != nil {
+			processes = append(processes, j.process)
+		}
+	}
+	if m.capture.running && m.capture.process != nil {
+		processes = append(processes, m.capture.process)
+	}
+	for _, process := range m.apps.execs {
+		processes = append(processes, process)
+	}
+
+	var wg sync.WaitGroup
+	for _, process := range processes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			process.Stop()
+		}()
+	}
+	wg.Wait()
+}
+
+func (m *Model) runningJobCount() int {
+	count := 0
+	for _, j := range m.jobs.jobs {
+		if j.status == jobRunning {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *Model) openJobs() {
+	m.launcherInput.Blur()
+	m.mode = ModeJobs
+	if m.jobs.cursor >= len(m.jobs.jobs) {
+		m.jobs.cursor = len(m.jobs.jobs) - 1
+	}
+	if m.jobs.cursor < 0 {
+		m.jobs.cursor = 0
+	}
+}
+
+func (m *Model) updateJobs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.keyAction(scopeJobs, msg.String()) {
+	case "jobs.back":
+		m.openLauncher()
+	case "jobs.up":
+		if m.jobs.cursor > 0 {
+			m.jobs.cursor--
+		}
+	case "jobs.down":
+		if m.jobs.cursor < len(m.jobs.jobs)-1 {
+			m.jobs.cursor++
+		}
+	case "jobs.stop":
+		m.stopJob(m.selectedJob())
+	case "jobs.restart":
+		return m, m.restartJob(m.selectedJob())
+	case "jobs.remove":
+		if j := m.selectedJob(); j != nil && j.status != jobRunning {
+			m.jobs.jobs = append(m.jobs.jobs[:m.jobs.cursor], m.jobs.jobs[m.jobs.cursor+1:]...)
+			if m.jobs.cursor >= len(m.jobs.jobs) && m.jobs.cursor > 0 {
+				m.jobs.cursor--
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) jobsViewState(height int) jobsview.ViewState {
+	rows := make([]jobsview.Job, len(m.jobs.jobs))
+	for i, j := range m.jobs.jobs {
+		ended := j.ended
+		if ended.IsZero() {
+			ended = time.Now()
+		}
+		rows[i] = jobsview.Job{
+			Label:    j.command.Label,
+			Status:   string(j.status),
+			Duration: ended.Sub(j.started).Round(time.Second).String(),
+		}
+		if j.err != nil && j.status == jobFailed {
+			rows[i].Detail = formatCommandExecError(j.err)
+		}
+	}
+	state := jobsview.ViewState{
+		Jobs:   rows,
+		Cursor: m.jobs.cursor,
+		Footer: m.keyHints("jobs.stop", "stop", "jobs.restart", "restart", "jobs.remove", "remove", "jobs.back", "back"),
+		Width:  m.width,
+		Height: height,
+	}
+	if j := m.selectedJob(); j != nil && j.output != nil {
+		state.Output = j.output.Tail(jobsview.OutputRows(height))
+	}
+	return state
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 const commandLauncherOpen = "launcher.open"
@@ -13,16 +16,81 @@ var defaultMainCommandShortcuts = map[string][]string{
 	commandLauncherOpen: {"ctrl+p", "ctrl+k", "alt+p"},
 }
 
-var reservedShortcuts = map[string]string{
-	"ctrl+c": "quit",
+// chordTimeout is how long a started key sequence waits for its next key.
+const chordTimeout = 1500 * time.Millisecond
+
+// leaderToken stands for the configured leader key inside a shortcut.
+const leaderToken = "leader"
+
+type chordExpiredMsg struct {
+	seq int
 }
 
-func (m *Model) resolveMainCommandIDForKey(key string) (string, bool) {
+// feedShortcutKey advances the pending key sequence with key. It returns
+// the command to run once a binding completes, and consumed is set when the
+// key belonged to a sequence, complete or not.
+func (m *Model) feedShortcutKey(key string) (commandID string, cmd tea.Cmd, consumed bool) {
 	if m.shortcutCommands == nil {
-		_ = m.applyShortcuts(nil)
+		_ = m.applyShortcuts(nil, nil)
+	}
+	key = shortcutKeyName(key)
+	pending := len(m.chord) > 0
+	if pending && key == "esc" {
+		m.chord = nil
+		return "", nil, true
+	}
+	sequence := strings.Join(append(append([]string{}, m.chord...), key), " ")
+	if commandID, ok := m.shortcutCommands[sequence]; ok {
+		m.chord = nil
+		return commandID, nil, true
+	}
+	if _, ok := m.shortcutPrefixes[sequence]; ok {
+		m.chord = strings.Fields(sequence)
+		m.chordSeq++
+		seq := m.chordSeq
+		return "", tea.Tick(chordTimeout, func(time.Time) tea.Msg {
+			return chordExpiredMsg{seq: seq}
+		}), true
+	}
+	// An unbound key ends a started sequence and is swallowed with it.
+	m.chord = nil
+	return "", nil, pending
+}
+
+// shortcutKeyName turns a key message string into the name used in
+// bindings.
+func shortcutKeyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return normalizeShortcutKey(key)
+}
+
+// chordHint describes the pending sequence and the keys that can follow it.
+func (m Model) chordHint() string {
+	sequence := strings.Join(m.chord, " ")
+	next := make([]string, 0)
+	for key, commandID := range m.shortcutCommands {
+		if rest, ok := strings.CutPrefix(key, sequence+" "); ok {
+			next = append(next, rest+" "+m.commandLabel(commandID))
+		}
+	}
+	sort.Strings(next)
+	if len(next) > 4 {
+		next = append(next[:4], "…")
+	}
+	hint := sequence + " …"
+	if len(next) > 0 {
+		hint += " " + strings.Join(next, " · ")
+	}
+	return hint + " · esc cancels"
+}
+
+func (m Model) commandLabel(commandID string) string {
+	if command, ok := m.findCommandByID(commandID); ok {
+		return command.Label
 	}
-	commandID, ok := m.shortcutCommands[normalizeShortcutKey(key)]
-	return commandID, ok
+	return commandID
 }
 
 func (m Model) primaryShortcut(commandID string, fallback string) string {
@@ -41,61 +109,240 @@ func (m Model) shortcutsHint(commandID string, fallback string) string {
 	return strings.Join(shortcuts, "/")
 }
 
-func (m *Model) applyShortcuts(overrides map[string][]string) error {
-	bindings := copyShortcutBindings(defaultMainCommandShortcuts)
-	shortcutErrors := make([]error, 0)
+// applyShortcuts resolves command shortcuts from layers, lowest precedence
+// first, on top of the defaults. A layer replaces whatever earlier layers
+// bound to the same command. Every binding is recorded with its origin, and
+// dropped ones with the reason; shortcuts of inactive commands stay in
+// config on purpose, so dropping them is not a problem.
+func (m *Model) applyShortcuts(layers []bindingLayer, inactive map[string]struct{}) error {
+	m.chord = nil
 	known := m.knownCommandIDs()
-	commandIDs := make([]string, 0, len(overrides))
-	for commandID := range overrides {
-		commandIDs = append(commandIDs, commandID)
-	}
-	sort.Strings(commandIDs)
-	for _, commandID := range commandIDs {
-		if _, ok := known[commandID]; !ok {
-			shortcutErrors = append(shortcutErrors, fmt.Errorf("unknown shortcut command id: %s", commandID))
-			continue
+	layers = append([]bindingLayer{{origin: originDefault, bindings: defaultMainCommandShortcuts}}, layers...)
+
+	var records []bindingRecord
+	pending := make(map[string][]bindingRecord)
+	for _, layer := range layers {
+		commandIDs := make([]string, 0, len(layer.bindings))
+		for commandID := range layer.bindings {
+			commandIDs = append(commandIDs, commandID)
+		}
+		sort.Strings(commandIDs)
+		for _, commandID := range commandIDs {
+			_, disabled := inactive[commandID]
+			_, exists := known[commandID]
+			candidates := make([]bindingRecord, 0, len(layer.bindings[commandID]))
+			seen := make(map[string]struct{})
+			for _, raw := range layer.bindings[commandID] {
+				raw = strings.TrimSpace(raw)
+				key := normalizeShortcutKey(raw)
+				if _, dup := seen[key]; dup || key == "" {
+					continue
+				}
+				seen[key] = struct{}{}
+				record := newBindingRecord(raw, key, commandID, bindingScopeCommand, layer.origin)
+				expanded, err := m.expandLeader(key)
+				switch {
+				case disabled:
+					record.Dropped = "belongs to a disabled or hidden command"
+					record.quiet = true
+				case !exists:
+					record.Dropped = "targets an unknown command"
+				case err != nil:
+					record.Dropped = "uses the leader key but no leader is set"
+				default:
+					record.Key = expanded
+					if record.Raw == "" && expanded != key {
+						record.Raw = key
+					}
+					candidates = append(candidates, record)
+					continue
+				}
+				records = append(records, record)
+			}
+			if disabled || !exists {
+				continue
+			}
+			for _, old := range pending[commandID] {
+				if _, kept := seen[normalizeShortcutKey(old.written())]; !kept {
+					old.Dropped = "was replaced by the " + layer.origin + " config"
+					old.quiet = true
+					records = append(records, old)
+				}
+			}
+			pending[commandID] = candidates
 		}
-		keys := normalizeShortcutKeys(overrides[commandID])
-		bindings[commandID] = keys
 	}
 
-	reverse := make(map[string]string)
-	allIDs := make([]string, 0, len(bindings))
-	for commandID := range bindings {
+	allIDs := make([]string, 0, len(pending))
+	for commandID := range pending {
 		allIDs = append(allIDs, commandID)
 	}
 	sort.Strings(allIDs)
-	for _, commandID := range allIDs {
-		for _, key := range bindings[commandID] {
-			if reason, reserved := reservedShortcuts[key]; reserved {
-				shortcutErrors = append(shortcutErrors, fmt.Errorf("shortcut %s is reserved for %s", key, reason))
-				continue
+	// Spellbook suggestions only take keys that no other binding uses, so
+	// they are placed last.
+	owners := make(map[string]*bindingRecord)
+	for _, suggestions := range []bool{false, true} {
+		for _, commandID := range allIDs {
+			for i := range pending[commandID] {
+				record := &pending[commandID][i]
+				if record.suggested() != suggestions {
+					continue
+				}
+				if reason, reserved := m.reservedShortcut(record.Key); reserved {
+					record.Dropped = "is reserved for " + reason
+					continue
+				}
+				if owner, taken := owners[record.Key]; taken {
+					record.Dropped = "is already used by " + owner.Target
+					if owner.written() != record.written() {
+						record.Dropped += ", written " + owner.written()
+					}
+					continue
+				}
+				if suggestions {
+					if reason := prefixClash(owners, record.Key); reason != "" {
+						record.Dropped = reason
+						continue
+					}
+				}
+				owners[record.Key] = record
 			}
-			if existingID, exists := reverse[key]; exists && existingID != commandID {
-				shortcutErrors = append(shortcutErrors, fmt.Errorf("shortcut %s conflicts between %s and %s", key, existingID, commandID))
-				continue
+		}
+	}
+
+	// A sequence can never complete when one of its prefixes is bound, so
+	// the longer binding is dropped.
+	keys := make([]string, 0, len(owners))
+	for key := range owners {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	prefixes := make(map[string]struct{})
+	for _, key := range keys {
+		steps := strings.Fields(key)
+		shadowed := false
+		for i := 1; i < len(steps) && !shadowed; i++ {
+			prefix := strings.Join(steps[:i], " ")
+			if owner, bound := owners[prefix]; bound {
+				owners[key].Dropped = "is shadowed by " + prefix + " of " + owner.Target
+				shadowed = true
+			}
+		}
+		if shadowed {
+			delete(owners, key)
+			continue
+		}
+		for i := 1; i < len(steps); i++ {
+			prefixes[strings.Join(steps[:i], " ")] = struct{}{}
+		}
+	}
+
+	bindings := make(map[string][]string, len(pending))
+	reverse := make(map[string]string, len(owners))
+	for _, commandID := range allIDs {
+		kept := make([]string, 0, len(pending[commandID]))
+		for _, record := range pending[commandID] {
+			// A suggestion that does not fit is expected, not a problem.
+			record.quiet = record.quiet || record.suggested()
+			records = append(records, record)
+			if record.Dropped == "" {
+				kept = append(kept, record.Key)
+				reverse[record.Key] = commandID
 			}
-			reverse[key] = commandID
 		}
+		bindings[commandID] = kept
 	}
+
 	m.commandShortcuts = bindings
 	m.shortcutCommands = reverse
-	return errors.Join(shortcutErrors...)
+	m.shortcutPrefixes = prefixes
+	m.shortcutRecords = records
+	return errors.Join(recordErrors("shortcut", records)...)
+}
+
+// prefixClash explains why key cannot be added next to owners without one
+// sequence shadowing another.
+func prefixClash(owners map[string]*bindingRecord, key string) string {
+	steps := strings.Fields(key)
+	for i := 1; i < len(steps); i++ {
+		prefix := strings.Join(steps[:i], " ")
+		if owner, bound := owners[prefix]; bound {
+			return "would be shadowed by " + prefix + " of " + owner.Target
+		}
+	}
+	longer := make([]string, 0)
+	for bound := range owners {
+		if strings.HasPrefix(bound, key+" ") {
+			longer = append(longer, bound)
+		}
+	}
+	if len(longer) == 0 {
+		return ""
+	}
+	sort.Strings(longer)
+	return "would shadow " + longer[0] + " of " + owners[longer[0]].Target
+}
+
+// expandLeader replaces the leader token of a shortcut with the configured
+// leader key.
+func (m Model) expandLeader(key string) (string, error) {
+	steps := strings.Fields(key)
+	for i, step := range steps {
+		if step != leaderToken && step != "<"+leaderToken+">" {
+			continue
+		}
+		if m.leaderKey == "" {
+			return "", fmt.Errorf("shortcut %s uses the leader key but no leader is set", key)
+		}
+		steps[i] = m.leaderKey
+	}
+	return strings.Join(steps, " "), nil
 }
 
-func copyShortcutBindings(input map[string][]string) map[string][]string {
-	out := make(map[string][]string, len(input))
-	for commandID, keys := range input {
-		copied := make([]string, 0, len(keys))
-		for _, key := range keys {
-			key = normalizeShortcutKey(key)
-			if key != "" {
-				copied = append(copied, key)
+// shortcutConflict explains why key cannot be bound to one of owners, using
+// the rules of applyShortcuts against the current bindings. Spellbook
+// suggestions never stand in the way, since config wins over them.
+func (m Model) shortcutConflict(key string, owners ...string) error {
+	key, err := m.expandLeader(key)
+	if err != nil {
+		return err
+	}
+	if reason, reserved := m.reservedShortcut(key); reserved {
+		return errors.New("shortcut " + key + " is reserved for " + reason)
+	}
+	suggested := make(map[string]struct{})
+	for _, record := range m.shortcutRecords {
+		if record.suggested() && record.Dropped == "" {
+			suggested[record.Key] = struct{}{}
+		}
+	}
+	blocks := func(bound, commandID string) bool {
+		if _, ok := suggested[bound]; ok {
+			return false
+		}
+		for _, owner := range owners {
+			if owner != "" && owner == commandID {
+				return false
 			}
 		}
-		out[commandID] = copied
+		return true
 	}
-	return out
+	if owner, taken := m.shortcutCommands[key]; taken && blocks(key, owner) {
+		return errors.New("shortcut " + key + " is already used by " + owner)
+	}
+	steps := strings.Fields(key)
+	for i := 1; i < len(steps); i++ {
+		prefix := strings.Join(steps[:i], " ")
+		if owner, taken := m.shortcutCommands[prefix]; taken && blocks(prefix, owner) {
+			return errors.New("shortcut " + key + " would be shadowed by " + prefix + " of " + owner)
+		}
+	}
+	for bound, owner := range m.shortcutCommands {
+		if strings.HasPrefix(bound, key+" ") && blocks(bound, owner) {
+			return errors.New("shortcut " + key + " would shadow " + bound + " of " + owner)
+		}
+	}
+	return nil
 }
 
 func normalizeShortcutKeys(keys []string) []string {
@@ -115,13 +362,25 @@ func normalizeShortcutKeys(keys []string) []string {
 	return out
 }
 
+// normalizeShortcutKey lowercases a key or key sequence and separates the
+// keys of a sequence with single spaces.
 func normalizeShortcutKey(key string) string {
-	return strings.ToLower(strings.TrimSpace(key))
+	return strings.Join(strings.Fields(strings.ToLower(key)), " ")
 }
 
 func (m Model) knownCommandIDs() map[string]struct{} {
 	ids := map[string]struct{}{
-		commandLauncherOpen: {},
+		commandLauncherOpen:    {},
+		commandMarketplaceOpen: {},
+		commandJobsOpen:        {},
+		commandSettingsOpen:    {},
+		commandKeysOpen:        {},
+	}
+	for _, app := range m.appCommands() {
+		ids[app.ID] = struct{}{}
+	}
+	for _, action := range m.appActionCommands() {
+		ids[action.ID] = struct{}{}
 	}
 	if m.state != nil {
 		for _, cmd := range m.state.Commands {
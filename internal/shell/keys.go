@@ -0,0 +1,485 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	helpview "github.com/Noudea/glyph/internal/view/help"
+)
+
+// keyScope groups actions that are active at the same time. Global actions
+// apply in every mode and win over the others.
+type keyScope string
+
+const (
+	scopeGlobal      keyScope = "global"
+	scopeMain        keyScope = "main"
+	scopeLauncher    keyScope = "launcher"
+	scopeMarketplace keyScope = "marketplace"
+	scopeInstall     keyScope = "install"
+	scopeChain       keyScope = "chain"
+	scopeJobs        keyScope = "jobs"
+	scopeOutput      keyScope = "output"
+	scopeSearch      keyScope = "search"
+	scopeConfirm     keyScope = "confirm"
+	scopeForm        keyScope = "form"
+	scopeSettings    keyScope = "settings"
+	scopeKeys        keyScope = "keys"
+)
+
+// keyScopeTitles orders the scopes in the help overlay.
+var keyScopeTitles = []struct {
+	scope keyScope
+	title string
+}{
+	{scopeGlobal, "Everywhere"},
+	{scopeMain, "Apps"},
+	{scopeLauncher, "Command palette"},
+	{scopeMarketplace, "Marketplace"},
+	{scopeInstall, "Marketplace install scope"},
+	{scopeChain, "Chain progress"},
+	{scopeJobs, "Background jobs"},
+	{scopeOutput, "Output viewer"},
+	{scopeSearch, "Output search"},
+	{scopeConfirm, "Confirmation"},
+	{scopeForm, "Forms"},
+	{scopeSettings, "Settings"},
+	{scopeKeys, "Keymap inspector"},
+}
+
+// keyAction is a bindable action. Its ID is "<scope>.<name>", the name used
+// under "keys" in config.
+type keyAction struct {
+	id   string
+	help string
+	keys []string
+	// typing marks actions that fire while a text input has focus; keys
+	// that type text cannot be bound to them.
+	typing bool
+	// required actions keep their default keys when config unbinds them.
+	required bool
+}
+
+func (a keyAction) scope() keyScope {
+	scope, _, _ := strings.Cut(a.id, ".")
+	return keyScope(scope)
+}
+
+var keyActions = []keyAction{
+	{id: "global.quit", help: "quit", keys: []string{"ctrl+c"}, typing: true, required: true},
+	{id: "global.help", help: "key bindings", keys: []string{"f1"}, typing: true},
+
+	{id: "main.next-app", help: "next app", keys: []string{"alt+]", "ctrl+right"}, typing: true},
+	{id: "main.prev-app", help: "previous app", keys: []string{"alt+[", "ctrl+left"}, typing: true},
+	{id: "main.close-app", help: "close app", keys: []string{"ctrl+w"}, typing: true},
+
+	{id: "launcher.close", help: "close", keys: []string{"esc"}, typing: true},
+	{id: "launcher.run", help: "run", keys: []string{"enter"}, typing: true},
+	{id: "launcher.up", help: "move up", keys: []string{"up"}, typing: true},
+	{id: "launcher.down", help: "move down", keys: []string{"down"}, typing: true},
+	{id: "launcher.next-section", help: "next section", keys: []string{"tab"}, typing: true},
+	{id: "launcher.prev-section", help: "previous section", keys: []string{"shift+tab"}, typing: true},
+	{id: "launcher.fold", help: "fold section", keys: []string{"ctrl+t"}, typing: true},
+	{id: "launcher.preview", help: "preview", keys: []string{"ctrl+o"}, typing: true},
+	{id: "launcher.save", help: "save as command", keys: []string{"ctrl+s"}, typing: true},
+
+	{id: "marketplace.back", help: "back", keys: []string{"esc"}},
+	{id: "marketplace.up", help: "move up", keys: []string{"up", "k"}},
+	{id: "marketplace.down", help: "move down", keys: []string{"down", "j"}},
+	{id: "marketplace.install", help: "install", keys: []string{"i"}},
+	{id: "marketplace.uninstall", help: "uninstall", keys: []string{"u"}},
+	{id: "marketplace.update", help: "update", keys: []string{"U"}},
+	{id: "install.global", help: "install globally", keys: []string{"g"}},
+	{id: "install.project", help: "install in project", keys: []string{"p"}},
+	{id: "install.cancel", help: "cancel", keys: []string{"esc"}},
+
+	{id: "chain.return", help: "return", keys: []string{"enter", "esc"}},
+	{id: "chain.rerun", help: "re-run", keys: []string{"r"}},
+
+	{id: "jobs.back", help: "back", keys: []string{"esc"}},
+	{id: "jobs.up", help: "move up", keys: []string{"up", "k"}},
+	{id: "jobs.down", help: "move down", keys: []string{"down", "j"}},
+	{id: "jobs.stop", help: "stop", keys: []string{"s"}},
+	{id: "jobs.restart", help: "restart", keys: []string{"r"}},
+	{id: "jobs.remove", help: "remove", keys: []string{"d"}},
+
+	{id: "output.back", help: "back", keys: []string{"esc", "q"}},
+	{id: "output.up", help: "scroll up", keys: []string{"up", "k"}},
+	{id: "output.down", help: "scroll down", keys: []string{"down", "j"}},
+	{id: "output.page-up", help: "page up", keys: []string{"pgup"}},
+	{id: "output.page-down", help: "page down", keys: []string{"pgdown", "space"}},
+	{id: "output.top", help: "top", keys: []string{"home", "g"}},
+	{id: "output.bottom", help: "bottom", keys: []string{"end", "G"}},
+	{id: "output.search", help: "search", keys: []string{"/"}},
+	{id: "output.next-match", help: "next match", keys: []string{"n"}},
+	{id: "output.prev-match", help: "previous match", keys: []string{"N"}},
+	{id: "output.copy-line", help: "copy line", keys: []string{"c"}},
+	{id: "output.copy-all", help: "copy all", keys: []string{"C"}},
+	{id: "output.rerun", help: "re-run", keys: []string{"r"}},
+	{id: "output.cancel", help: "cancel run", keys: []string{"x"}},
+	{id: "search.find", help: "find", keys: []string{"enter"}, typing: true},
+	{id: "search.cancel", help: "cancel", keys: []string{"esc"}, typing: true},
+
+	{id: "confirm.yes", help: "run", keys: []string{"y", "Y"}},
+	{id: "confirm.no", help: "cancel", keys: []string{"n", "N"}},
+	{id: "confirm.submit", help: "confirm typed id", keys: []string{"enter"}, typing: true},
+	{id: "confirm.cancel", help: "cancel", keys: []string{"esc"}, typing: true},
+
+	{id: "form.next", help: "next field", keys: []string{"tab", "down"}, typing: true},
+	{id: "form.prev", help: "previous field", keys: []string{"shift+tab", "up"}, typing: true},
+	{id: "form.submit", help: "save", keys: []string{"enter"}, typing: true},
+	{id: "form.cancel", help: "cancel", keys: []string{"esc"}, typing: true},
+	{id: "form.toggle", help: "switch scope", keys: []string{"space", "left", "right"}},
+	{id: "form.global", help: "global scope", keys: []string{"g"}},
+	{id: "form.project", help: "project scope", keys: []string{"p"}},
+
+	{id: "settings.back", help: "back", keys: []string{"esc"}},
+	{id: "settings.up", help: "move up", keys: []string{"up", "k"}},
+	{id: "settings.down", help: "move down", keys: []string{"down", "j"}},
+	{id: "settings.move-up", help: "move entry up", keys: []string{"shift+up", "K"}},
+	{id: "settings.move-down", help: "move entry down", keys: []string{"shift+down", "J"}},
+	{id: "settings.scope", help: "switch scope", keys: []string{"tab", "shift+tab"}},
+	{id: "settings.global", help: "global config", keys: []string{"g"}},
+	{id: "settings.project", help: "project config", keys: []string{"p"}},
+	{id: "settings.new", help: "new", keys: []string{"n"}},
+	{id: "settings.edit", help: "edit", keys: []string{"enter", "e"}},
+	{id: "settings.toggle", help: "enable/disable", keys: []string{"space"}},
+	{id: "settings.delete", help: "delete", keys: []string{"d"}},
+	{id: "settings.confirm-delete", help: "confirm delete", keys: []string{"y"}},
+
+	{id: "keys.back", help: "back", keys: []string{"esc", "q"}},
+	{id: "keys.up", help: "move up", keys: []string{"up", "k"}},
+	{id: "keys.down", help: "move down", keys: []string{"down", "j"}},
+	{id: "keys.dropped", help: "show dropped only", keys: []string{"d"}},
+}
+
+// keymap is the resolved binding of every action.
+type keymap struct {
+	// actions maps a key to the action it triggers, per scope.
+	actions map[keyScope]map[string]string
+	// keys lists the keys of each action in binding order.
+	keys map[string][]string
+}
+
+// buildKeymap resolves action keys from layers, lowest precedence first, on
+// top of the defaults; a layer replaces the keys earlier ones gave an
+// action. Keys are checked per scope: a key may trigger one action of a
+// scope, never one that a global action already uses, and actions that run
+// while typing only take keys that do not type text.
+func buildKeymap(layers []bindingLayer) (keymap, []bindingRecord, []error) {
+	var errs []error
+	var records []bindingRecord
+	known := make(map[string]struct{}, len(keyActions))
+	for _, action := range keyActions {
+		known[action.id] = struct{}{}
+	}
+	for _, layer := range layers {
+		ids := make([]string, 0, len(layer.bindings))
+		for id := range layer.bindings {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if _, ok := known[id]; ok {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("unknown key action: %s", id))
+			for _, record := range actionRecords(layer.bindings[id], id, layer.origin) {
+				record.Dropped = "targets an unknown action"
+				record.quiet = true
+				records = append(records, record)
+			}
+		}
+	}
+
+	out := keymap{
+		actions: make(map[keyScope]map[string]string),
+		keys:    make(map[string][]string, len(keyActions)),
+	}
+	for _, action := range keyActions {
+		candidates := actionRecords(action.keys, action.id, originDefault)
+		for _, layer := range layers {
+			override, ok := layer.bindings[action.id]
+			if !ok {
+				continue
+			}
+			next := actionRecords(override, action.id, layer.origin)
+			if len(next) == 0 && action.required {
+				errs = append(errs, fmt.Errorf("%s needs at least one key", action.id))
+				continue
+			}
+			for _, old := range candidates {
+				if !slices.ContainsFunc(next, func(r bindingRecord) bool { return r.Key == old.Key }) {
+					old.Dropped = "was replaced by the " + layer.origin + " config"
+					old.quiet = true
+					records = append(records, old)
+				}
+			}
+			candidates = next
+		}
+
+		scope := action.scope()
+		bound := out.actions[scope]
+		if bound == nil {
+			bound = make(map[string]string)
+			out.actions[scope] = bound
+		}
+		kept := make([]string, 0, len(candidates))
+		for _, record := range candidates {
+			key := record.Key
+			globalOwner, globalTaken := out.actions[scopeGlobal][key]
+			owner, taken := bound[key]
+			switch {
+			case strings.Contains(key, " "):
+				record.Dropped = "is a key sequence; only command shortcuts take sequences"
+			case action.typing && typesText(key):
+				record.Dropped = "would be typed into the input"
+			case globalTaken && scope != scopeGlobal:
+				record.Dropped = "is taken by " + globalOwner
+			case taken:
+				record.Dropped = "is taken by " + owner
+			default:
+				bound[key] = action.id
+				kept = append(kept, key)
+			}
+			records = append(records, record)
+		}
+		out.keys[action.id] = kept
+	}
+	return out, records, append(errs, recordErrors("key", records)...)
+}
+
+// actionRecords normalizes keys like command shortcuts do but keeps the
+// case of single letters, which tells "n" and "N" apart.
+func actionRecords(keys []string, id string, origin string) []bindingRecord {
+	scope, _, _ := strings.Cut(id, ".")
+	seen := make(map[string]struct{}, len(keys))
+	out := make([]bindingRecord, 0, len(keys))
+	for _, raw := range keys {
+		raw = strings.TrimSpace(raw)
+		key := actionKeyName(raw)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, newBindingRecord(raw, key, id, scope, origin))
+	}
+	return out
+}
+
+// typesText reports keys a focused text input would insert.
+func typesText(key string) bool {
+	return key == "space" || len([]rune(key)) == 1
+}
+
+// actionKeyName turns a key message string into the name used in the
+// keymap. Single characters keep their case.
+func actionKeyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	if len([]rune(key)) == 1 {
+		return key
+	}
+	return normalizeShortcutKey(key)
+}
+
+// keyAction returns the action key triggers in scope, checking global
+// actions first.
+func (m Model) keyAction(scope keyScope, key string) string {
+	key = actionKeyName(key)
+	if id, ok := m.keys.actions[scopeGlobal][key]; ok {
+		return id
+	}
+	return m.keys.actions[scope][key]
+}
+
+// actionKeys returns the keys bound to an action.
+func (m Model) actionKeys(id string) []string {
+	if m.keys.keys == nil {
+		return nil
+	}
+	return m.keys.keys[id]
+}
+
+// reservedShortcut reports keys a command shortcut cannot use because an
+// action claims them where command shortcuts also fire: everywhere for
+// global actions, in the main view for app actions, and in the palette for
+// keys it passes to shortcuts. A sequence is reserved when its first key
+// is.
+func (m Model) reservedShortcut(key string) (string, bool) {
+	first, _, _ := strings.Cut(key, " ")
+	for _, candidate := range []string{key, first} {
+		if id, ok := m.keys.actions[scopeGlobal][candidate]; ok {
+			return id, true
+		}
+		if id, ok := m.keys.actions[scopeMain][candidate]; ok {
+			return id, true
+		}
+		if id, ok := m.keys.actions[scopeLauncher][candidate]; ok && isLauncherShortcutCandidate(candidate) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// keyHint renders hint text for actions. ids may join several actions with
+// "|", which shows the first key of each, as in "↑/↓ move".
+func (m Model) keyHint(ids string, text string) string {
+	var keys []string
+	actions := strings.Split(ids, "|")
+	for _, id := range actions {
+		bound := m.actionKeys(id)
+		if len(actions) == 1 && len(bound) > 2 {
+			bound = bound[:2]
+		}
+		if len(actions) > 1 && len(bound) > 1 {
+			bound = bound[:1]
+		}
+		for _, key := range bound {
+			keys = append(keys, displayKey(key))
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	return strings.Join(keys, "/") + " " + text
+}
+
+// keyHints joins id/text pairs into a hint line, leaving out unbound
+// actions.
+func (m Model) keyHints(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if hint := m.keyHint(pairs[i], pairs[i+1]); hint != "" {
+			parts = append(parts, hint)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func displayKey(key string) string {
+	switch key {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "shift+up":
+		return "shift+↑"
+	case "shift+down":
+		return "shift+↓"
+	}
+	return key
+}
+
+// applyKeys rebuilds the keymap from the "keys" config sections.
+func (m *Model) applyKeys(layers []bindingLayer) error {
+	keys, records, errs := buildKeymap(layers)
+	m.keys = keys
+	m.keyRecords = records
+	return errors.Join(errs...)
+}
+
+// keyScope returns the scope whose actions the current mode handles.
+func (m Model) keyScope() keyScope {
+	switch m.mode {
+	case ModeMain:
+		return scopeMain
+	case ModeLauncher:
+		return scopeLauncher
+	case ModeMarketplace:
+		if m.marketplace.confirmInstall != "" {
+			return scopeInstall
+		}
+		return scopeMarketplace
+	case ModeChain:
+		return scopeChain
+	case ModeJobs:
+		return scopeJobs
+	case ModeOutput:
+		if m.capture.searching {
+			return scopeSearch
+		}
+		return scopeOutput
+	case ModeConfirm:
+		return scopeConfirm
+	case ModeSaveCommand:
+		return scopeForm
+	case ModeSettings:
+		if m.settings.editing {
+			return scopeForm
+		}
+		return scopeSettings
+	case ModeKeys:
+		return scopeKeys
+	}
+	return scopeGlobal
+}
+
+func keyScopeTitle(scope keyScope) string {
+	for _, entry := range keyScopeTitles {
+		if entry.scope == scope {
+			return entry.title
+		}
+	}
+	return string(scope)
+}
+
+// helpViewState lists the bindings of the current mode, the global ones
+// and the command shortcuts.
+func (m Model) helpViewState(height int) helpview.ViewState {
+	scopes := []keyScope{m.keyScope()}
+	if scopes[0] != scopeGlobal {
+		scopes = append(scopes, scopeGlobal)
+	}
+	sections := make([]helpview.Section, 0, len(scopes)+1)
+	for _, scope := range scopes {
+		section := helpview.Section{Title: keyScopeTitle(scope)}
+		for _, action := range keyActions {
+			keys := m.actionKeys(action.id)
+			if action.scope() != scope || len(keys) == 0 {
+				continue
+			}
+			display := make([]string, len(keys))
+			for i, key := range keys {
+				display[i] = displayKey(key)
+			}
+			section.Entries = append(section.Entries, helpview.Entry{
+				Keys: strings.Join(display, " / "),
+				Text: action.help + "  " + action.id,
+			})
+		}
+		sections = append(sections, section)
+	}
+
+	shortcuts := helpview.Section{Title: "Command shortcuts"}
+	keys := make([]string, 0, len(m.shortcutCommands))
+	for key := range m.shortcutCommands {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		shortcuts.Entries = append(shortcuts.Entries, helpview.Entry{
+			Keys: key,
+			Text: m.commandLabel(m.shortcutCommands[key]),
+		})
+	}
+	sections = append(sections, shortcuts)
+
+	return helpview.ViewState{
+		Sections: sections,
+		Footer:   m.keyHints("global.help", "close") + ` · esc close · rebind under "keys" in config`,
+		Width:    m.width,
+		Height:   height,
+	}
+}
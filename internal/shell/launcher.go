@@ -0,0 +1,274 @@
+package shell
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Noudea/glyph/internal/core"
+	launcherview "github.com/Noudea/glyph/internal/view/launcher"
+)
+
+// launcherQuery is the parsed launcher input. Plain words match labels, IDs
+// and shortcuts; "group:name" narrows to matching sections, "@source" to a
+// command source (any of several) and "#tag" to tagged commands (all of
+// several). Input starting with ">" is shell text to run as is, and "?"
+// shows the query help.
+type launcherQuery struct {
+	text    string
+	group   string
+	sources []string
+	tags    []string
+
+	adhoc   bool
+	command string
+	help    bool
+}
+
+func parseLauncherQuery(input string) launcherQuery {
+	trimmed := strings.TrimSpace(input)
+	if rest, ok := strings.CutPrefix(trimmed, ">"); ok {
+		return launcherQuery{adhoc: true, command: strings.TrimSpace(rest)}
+	}
+	if strings.HasPrefix(trimmed, "?") {
+		return launcherQuery{help: true}
+	}
+
+	var query launcherQuery
+	var words []string
+	for _, field := range strings.Fields(strings.ToLower(trimmed)) {
+		if group, ok := strings.CutPrefix(field, "group:"); ok {
+			query.group = group
+			continue
+		}
+		if source, ok := strings.CutPrefix(field, "@"); ok && source != "" {
+			query.sources = append(query.sources, source)
+			continue
+		}
+		if tag, ok := strings.CutPrefix(field, "#"); ok && tag != "" {
+			query.tags = append(query.tags, tag)
+			continue
+		}
+		words = append(words, field)
+	}
+	query.text = strings.Join(words, " ")
+	return query
+}
+
+func (q launcherQuery) empty() bool {
+	return q.text == "" && q.group == "" && len(q.sources) == 0 && len(q.tags) == 0
+}
+
+func (q launcherQuery) matches(command core.Command) bool {
+	if q.group != "" &&
+		!strings.Contains(strings.ToLower(commandSection(command)), q.group) &&
+		!strings.Contains(strings.ToLower(command.Group), q.group) {
+		return false
+	}
+	if len(q.sources) > 0 && !slices.ContainsFunc(q.sources, func(source string) bool {
+		return strings.HasPrefix(command.Source, source) ||
+			(command.Spellbook != "" && strings.HasPrefix(strings.ToLower(command.Spellbook), source))
+	}) {
+		return false
+	}
+	for _, tag := range q.tags {
+		if !slices.ContainsFunc(command.Tags, func(candidate string) bool {
+			return strings.HasPrefix(candidate, tag)
+		}) {
+			return false
+		}
+	}
+	if q.text == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(command.Label), q.text) ||
+		strings.Contains(strings.ToLower(command.ID), q.text) ||
+		strings.Contains(strings.ToLower(command.Shortcut), q.text)
+}
+
+// launcherRow is either a section header or a command.
+type launcherRow struct {
+	section   string
+	header    bool
+	count     int
+	collapsed bool
+	command   core.Command
+}
+
+// commandSection names the launcher section a command is listed under:
+// its spellbook, or its group.
+func commandSection(command core.Command) string {
+	if command.Spellbook != "" {
+		return command.Spellbook
+	}
+	switch command.Group {
+	case "", groupCommands:
+		return "Commands"
+	case groupSystem:
+		return "Glyph"
+	case groupAdhoc:
+		return "Run"
+	case groupHistory:
+		return "History"
+	}
+	r, size := utf8.DecodeRuneInString(command.Group)
+	return string(unicode.ToUpper(r)) + command.Group[size:]
+}
+
+// sectionRank orders sections: the user's own commands first, then
+// imported and detected tasks, spellbooks, and Glyph's built-ins last.
+func sectionRank(command core.Command) int {
+	switch command.Group {
+	case groupAdhoc:
+		return -2
+	case groupHistory:
+		return -1
+	case groupSystem, groupApps:
+		return 3
+	case groupSpellbook:
+		return 2
+	case groupImports, groupDetected:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// launcherRows groups the filtered commands into sections. Collapsed
+// sections keep only their header, except while searching.
+func (m Model) launcherRows() []launcherRow {
+	commands := m.filteredCommands()
+	type section struct {
+		name     string
+		rank     int
+		commands []core.Command
+	}
+	var sections []*section
+	byName := make(map[string]*section)
+	for _, command := range commands {
+		name := commandSection(command)
+		s, ok := byName[name]
+		if !ok {
+			s = &section{name: name, rank: sectionRank(command)}
+			byName[name] = s
+			sections = append(sections, s)
+		}
+		s.commands = append(s.commands, command)
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		if sections[i].rank != sections[j].rank {
+			return sections[i].rank < sections[j].rank
+		}
+		if (sections[i].name == "Commands") != (sections[j].name == "Commands") {
+			return sections[i].name == "Commands"
+		}
+		return strings.ToLower(sections[i].name) < strings.ToLower(sections[j].name)
+	})
+
+	searching := strings.TrimSpace(m.launcherInput.Value()) != ""
+	rows := make([]launcherRow, 0, len(commands)+len(sections))
+	for _, s := range sections {
+		collapsed := m.launcherCollapsed[s.name] && !searching
+		rows = append(rows, launcherRow{section: s.name, header: true, count: len(s.commands), collapsed: collapsed})
+		if collapsed {
+			continue
+		}
+		for _, command := range s.commands {
+			rows = append(rows, launcherRow{section: s.name, command: command})
+		}
+	}
+	return rows
+}
+
+func (m Model) selectedLauncherRow() (launcherRow, bool) {
+	rows := m.launcherRows()
+	if m.launcherCursor < 0 || m.launcherCursor >= len(rows) {
+		return launcherRow{}, false
+	}
+	return rows[m.launcherCursor], true
+}
+
+// selectedCommand returns the command under the cursor, if any.
+func (m Model) selectedCommand() (core.Command, bool) {
+	row, ok := m.selectedLauncherRow()
+	if !ok || row.header {
+		return core.Command{}, false
+	}
+	return row.command, true
+}
+
+func (m *Model) toggleLauncherSection() {
+	row, ok := m.selectedLauncherRow()
+	if !ok {
+		return
+	}
+	if m.launcherCollapsed == nil {
+		m.launcherCollapsed = make(map[string]bool)
+	}
+	m.launcherCollapsed[row.section] = !m.launcherCollapsed[row.section]
+	for i, candidate := range m.launcherRows() {
+		if candidate.header && candidate.section == row.section {
+			m.launcherCursor = i
+			break
+		}
+	}
+}
+
+// jumpLauncherSection moves the cursor to the next (dir > 0) or previous
+// section header, wrapping around.
+func (m *Model) jumpLauncherSection(dir int) {
+	rows := m.launcherRows()
+	var headers []int
+	current := -1
+	for i, row := range rows {
+		if !row.header {
+			continue
+		}
+		if i <= m.launcherCursor {
+			current = len(headers)
+		}
+		headers = append(headers, i)
+	}
+	if len(headers) == 0 {
+		return
+	}
+	next := current + dir
+	if dir < 0 && current >= 0 && headers[current] < m.launcherCursor {
+		next = current
+	}
+	next = (next%len(headers) + len(headers)) % len(headers)
+	m.launcherCursor = headers[next]
+}
+
+// focusFirstCommand puts the cursor on the first command row, which is what
+// enter should run after the query changes.
+func (m *Model) focusFirstCommand() {
+	for i, row := range m.launcherRows() {
+		if !row.header {
+			m.launcherCursor = i
+			return
+		}
+	}
+	m.launcherCursor = 0
+}
+
+func (m Model) launcherViewRows() ([]launcherview.Row, int) {
+	rows := m.launcherRows()
+	out := make([]launcherview.Row, len(rows))
+	total := 0
+	for i, row := range rows {
+		out[i] = launcherview.Row{
+			Header:    row.header,
+			Title:     row.section,
+			Count:     row.count,
+			Collapsed: row.collapsed,
+			Command:   row.command,
+		}
+		if row.header {
+			total += row.count
+		}
+	}
+	return out, total
+}
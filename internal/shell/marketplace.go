@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/Noudea/glyph/internal/marketplace"
+	marketplaceview "github.com/Noudea/glyph/internal/view/marketplace"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -208,44 +209,42 @@ func (m *Model) updateMarketplace(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleMarketplaceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-
-	// If we're in the install-scope prompt, handle g/p/esc.
+	// If we're in the install-scope prompt, only its keys apply.
 	if m.marketplace.confirmInstall != "" {
-		switch key {
-		case "g":
+		switch m.keyAction(scopeInstall, msg.String()) {
+		case "install.global":
 			id := m.marketplace.confirmInstall
 			m.marketplace.confirmInstall = ""
 			return m, m.marketplaceInstallGlobal(id)
-		case "p":
+		case "install.project":
 			id := m.marketplace.confirmInstall
 			m.marketplace.confirmInstall = ""
 			return m, m.marketplaceInstallProject(id)
-		case "esc":
+		case "install.cancel":
 			m.marketplace.confirmInstall = ""
 			return m, nil
 		}
 		return m, nil
 	}
 
-	switch key {
-	case "esc":
+	switch m.keyAction(scopeMarketplace, msg.String()) {
+	case "marketplace.back":
 		m.openLauncher()
 		return m, nil
 
-	case "up", "k":
+	case "marketplace.up":
 		if m.marketplace.cursor > 0 {
 			m.marketplace.cursor--
 		}
 		return m, nil
 
-	case "down", "j":
+	case "marketplace.down":
 		if m.marketplace.cursor < len(m.marketplace.entries)-1 {
 			m.marketplace.cursor++
 		}
 		return m, nil
 
-	case "i":
+	case "marketplace.install":
 		entries := m.marketplace.entries
 		if len(entries) > 0 && m.marketplace.cursor >= 0 && m.marketplace.cursor < len(entries) {
 			e := entries[m.marketplace.cursor]
@@ -260,7 +259,7 @@ func (m *Model) handleMarketplaceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "u":
+	case "marketplace.uninstall":
 		entries := m.marketplace.entries
 		if len(entries) > 0 && m.marketplace.cursor >= 0 && m.marketplace.cursor < len(entries) {
 			e := entries[m.marketplace.cursor]
@@ -270,7 +269,7 @@ func (m *Model) handleMarketplaceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "U":
+	case "marketplace.update":
 		entries := m.marketplace.entries
 		if len(entries) > 0 && m.marketplace.cursor >= 0 && m.marketplace.cursor < len(entries) {
 			e := entries[m.marketplace.cursor]
@@ -284,6 +283,31 @@ func (m *Model) handleMarketplaceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// shortcutSuggestions reports the shortcuts a spellbook's commands suggest
+// and, for loaded commands, whether each one applied.
+func (m Model) shortcutSuggestions(sb marketplace.Spellbook) map[string][]marketplaceview.Suggestion {
+	out := make(map[string][]marketplaceview.Suggestion)
+	for _, cmd := range sb.Commands {
+		keys, err := decodeShortcuts(cmd.Shortcuts)
+		if err != nil {
+			continue
+		}
+		for _, key := range normalizeShortcutKeys(keys) {
+			suggestion := marketplaceview.Suggestion{Key: key}
+			for _, record := range m.shortcutRecords {
+				if !record.suggested() || record.Target != cmd.ID || normalizeShortcutKey(record.written()) != key {
+					continue
+				}
+				suggestion.Applied = record.Dropped == ""
+				suggestion.Skipped = record.Dropped
+				break
+			}
+			out[cmd.ID] = append(out[cmd.ID], suggestion)
+		}
+	}
+	return out
+}
+
 // resolveGlobalRoot returns the global ~/.glyph path.
 func (m *Model) resolveGlobalRoot() (string, error) {
 	ws, err := m.resolver.ResolveGlobal()
@@ -1,12 +1,16 @@
 package shell
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Noudea/glyph/internal/core"
 	"github.com/Noudea/glyph/internal/marketplace"
+	launcherview "github.com/Noudea/glyph/internal/view/launcher"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -36,6 +40,13 @@ const (
 	ModeMain
 	ModeLauncher
 	ModeMarketplace
+	ModeChain
+	ModeJobs
+	ModeOutput
+	ModeConfirm
+	ModeSaveCommand
+	ModeSettings
+	ModeKeys
 )
 
 // Model drives the UI.
@@ -47,12 +58,34 @@ type Model struct {
 	mode Mode
 
 	err string
-
-	launcherInput  textinput.Model
-	launcherCursor int
+	// exitErr is what went wrong while quitting; see ExitErr.
+	exitErr error
+
+	launcherInput   textinput.Model
+	launcherCursor  int
+	launcherPreview bool
+	// launcherPreviewCache holds the preview of launcherPreviewKey (the
+	// query and selected command) so rendering does not read files.
+	launcherPreviewCache *launcherview.Preview
+	launcherPreviewKey   string
+	// launcherCollapsed holds the launcher sections folded by the user.
+	launcherCollapsed map[string]bool
 
 	commandShortcuts map[string][]string
 	shortcutCommands map[string]string
+	// shortcutPrefixes holds the unfinished prefixes of key sequences.
+	shortcutPrefixes map[string]struct{}
+	leaderKey        string
+	// keys binds the actions of every mode; helpOpen shows them.
+	keys     keymap
+	helpOpen bool
+	// keyRecords and shortcutRecords list every configured binding with
+	// its origin and, if dropped, why; the keymap inspector shows them.
+	keyRecords      []bindingRecord
+	shortcutRecords []bindingRecord
+	// chord is the key sequence typed so far; chordSeq expires it.
+	chord    []string
+	chordSeq int
 
 	globalConfigPath  string
 	projectConfigPath string
@@ -60,6 +93,29 @@ type Model struct {
 	splashFrame int
 
 	marketplace marketplaceState
+	apps        appsState
+
+	chain   chainState
+	jobs    jobsState
+	capture captureState
+	confirm confirmState
+
+	saveCommand   saveCommandState
+	settings      settingsState
+	keysInspector keysInspectorState
+	history       []string
+	historyPath   string
+	// sessionPath keeps the open apps between runs.
+	sessionPath string
+
+	// events carries messages from processes running outside tea.Exec.
+	events chan tea.Msg
+
+	notice    string
+	noticeSeq int
+
+	// importFiles maps imported task files to their last seen mtime.
+	importFiles map[string]time.Time
 
 	width  int
 	height int
@@ -71,7 +127,7 @@ func NewModel(state *core.State, resolver core.WorkspaceResolver) *Model {
 	}
 	li := textinput.New()
 	li.Placeholder = "command"
-	li.CharLimit = 64
+	// No CharLimit: "> " queries are ad-hoc commands and must run as typed.
 	li.Width = 24
 	li.Prompt = "> "
 
@@ -89,21 +145,42 @@ func NewModel(state *core.State, resolver core.WorkspaceResolver) *Model {
 		startDir = "."
 	}
 
+	apps, appsErr := loadApps(resolver)
 	model := &Model{
 		state:         state,
 		resolver:      resolver,
 		startDir:      startDir,
 		mode:          ModeSplash,
 		launcherInput: li,
+		apps:          apps,
+		events:        make(chan tea.Msg, processEventBuffer),
+	}
+	model.keys, _, _ = buildKeymap(nil)
+	problems := []error{model.reloadConfig(), appsErr}
+	if globalRoot, err := resolver.ResolveGlobal(); err == nil {
+		model.historyPath = historyPath(globalRoot.RootPath)
+		history, err := loadHistory(model.historyPath)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("history: %w", err))
+		}
+		model.history = history
+		model.sessionPath = sessionPath(globalRoot.RootPath)
+		if len(state.OpenApps) == 0 {
+			if err := loadSession(model.sessionPath, state); err != nil {
+				problems = append(problems, fmt.Errorf("session: %w", err))
+			}
+		}
 	}
-	if err := model.reloadConfig(); err != nil {
+	if err := errors.Join(problems...); err != nil {
 		model.err = err.Error()
 	}
+	model.apps.workspace = model.moduleContext("").Workspace
 	return model
 }
 
 func (m *Model) Init() tea.Cmd {
-	cmds := make([]tea.Cmd, 0, 1)
+	cmds := make([]tea.Cmd, 0, 4)
+	cmds = append(cmds, m.waitForProcessEvent(), importPollCmd(), m.restoreApps())
 	if m.mode == ModeSplash {
 		cmds = append(cmds, splashTickCmd())
 	}
@@ -0,0 +1,67 @@
+package shell
+
+import (
+	"strings"
+	"sync"
+)
+
+// outputBufferLimit caps how many lines a captured run keeps in memory.
+const outputBufferLimit = 5000
+
+// outputBuffer collects process output line by line. It is safe for
+// concurrent writes from the process pipes and reads from the UI.
+type outputBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	partial string
+	notify  func()
+}
+
+func newOutputBuffer(notify func()) *outputBuffer {
+	return &outputBuffer{notify: notify}
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	text := b.partial + strings.ReplaceAll(string(p), "\r\n", "\n")
+	parts := strings.Split(text, "\n")
+	b.partial = parts[len(parts)-1]
+	b.lines = append(b.lines, parts[:len(parts)-1]...)
+	if over := len(b.lines) - outputBufferLimit; over > 0 {
+		b.lines = append([]string(nil), b.lines[over:]...)
+	}
+	notify := b.notify
+	b.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+	return len(p), nil
+}
+
+// Lines returns a copy of the complete lines plus any pending partial line.
+func (b *outputBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]string, 0, len(b.lines)+1)
+	out = append(out, b.lines...)
+	if b.partial != "" {
+		out = append(out, b.partial)
+	}
+	return out
+}
+
+// Tail returns at most n trailing lines.
+func (b *outputBuffer) Tail(n int) []string {
+	lines := b.Lines()
+	if n >= 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+func (b *outputBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = nil
+	b.partial = ""
+}
@@ -0,0 +1,245 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Noudea/glyph/internal/core"
+	launcherview "github.com/Noudea/glyph/internal/view/launcher"
+)
+
+// maxPreviewScriptBytes bounds how much of a script the preview reads.
+const maxPreviewScriptBytes = 64 * 1024
+
+// invocation is the fully resolved form of a command: exactly what
+// launchCommand would execute, without running anything.
+type invocation struct {
+	command core.Command
+	shell   string
+	argv    []string
+	dir     string
+	// env lists the variables the command sets or changes, as KEY=value.
+	env   []string
+	steps []invocation
+	err   error
+}
+
+type previewField struct {
+	label string
+	value string
+}
+
+func (m Model) resolveInvocation(command core.Command) invocation {
+	inv := invocation{command: command}
+	if command.Kind == core.CommandChain {
+		for _, step := range command.Steps {
+			resolved, err := m.resolveChainStep(command, step)
+			if err != nil {
+				inv.steps = append(inv.steps, invocation{command: step.Inline, err: err})
+				continue
+			}
+			if resolved.SourceFile == "" {
+				resolved.SourceFile = command.SourceFile
+			}
+			inv.steps = append(inv.steps, m.resolveInvocation(resolved))
+		}
+		return inv
+	}
+	if command.Kind != core.CommandExec {
+		return inv
+	}
+
+	process, err := shellExecCommand(command, m.startDir)
+	if err != nil {
+		inv.err = err
+		return inv
+	}
+	inv.shell = invocationShell(command)
+	inv.argv = process.Args
+	inv.dir = process.Dir
+	inv.env = envOverrides(process.Env)
+	return inv
+}
+
+func invocationShell(command core.Command) string {
+	switch {
+	case len(command.Argv) > 0:
+		return "none (argv)"
+	case command.Shell != "":
+		return command.Shell
+	case runtime.GOOS == "windows":
+		return "cmd (default)"
+	default:
+		return "sh (default)"
+	}
+}
+
+// envOverrides returns the entries of env that are missing from or differ
+// from Glyph's own environment.
+func envOverrides(env []string) []string {
+	inherited := make(map[string]string)
+	for _, pair := range os.Environ() {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			inherited[key] = value
+		}
+	}
+	var out []string
+	for _, pair := range env {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if current, exists := inherited[key]; exists && current == value {
+			continue
+		}
+		out = append(out, pair)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (inv invocation) fields() []previewField {
+	command := inv.command
+	fields := []previewField{{label: "id", value: command.ID}}
+	if command.Description != "" {
+		fields = append(fields, previewField{label: "about", value: command.Description})
+	}
+	if command.SourceFile != "" {
+		fields = append(fields, previewField{label: "source", value: command.SourceFile})
+	} else if command.Source != "" {
+		fields = append(fields, previewField{label: "source", value: command.Source})
+	}
+	if inv.err != nil {
+		return append(fields, previewField{label: "error", value: inv.err.Error()})
+	}
+	if command.Kind != core.CommandExec && command.Kind != core.CommandChain {
+		return append(fields, previewField{label: "kind", value: "built-in " + string(command.Kind)})
+	}
+
+	if command.Kind == core.CommandChain {
+		policy := "stop on first failure"
+		if !command.StopOnError {
+			policy = "continue on failure"
+		}
+		fields = append(fields, previewField{label: "steps", value: fmt.Sprintf("%d, %s", len(inv.steps), policy)})
+		for i, step := range inv.steps {
+			line := step.commandLine()
+			if step.err != nil {
+				line = "error: " + step.err.Error()
+			}
+			fields = append(fields, previewField{label: fmt.Sprintf("%d.", i+1), value: step.command.Label + " · " + line})
+		}
+		return fields
+	}
+
+	mode := "terminal"
+	if command.RunMode != core.RunTerminal {
+		mode = string(command.RunMode)
+	}
+	fields = append(fields,
+		previewField{label: "mode", value: mode},
+		previewField{label: "shell", value: inv.shell},
+		previewField{label: "argv", value: inv.commandLine()},
+		previewField{label: "cwd", value: inv.dir},
+	)
+	for _, pair := range inv.env {
+		fields = append(fields, previewField{label: "env", value: pair})
+	}
+	if command.Timeout > 0 {
+		fields = append(fields, previewField{label: "timeout", value: command.Timeout.String()})
+	}
+	if command.Confirm != core.ConfirmNone {
+		fields = append(fields, previewField{label: "confirm", value: string(command.Confirm)})
+	}
+	if command.Script != "" {
+		fields = append(fields, previewField{label: "script", value: command.Script})
+	}
+	return fields
+}
+
+func (inv invocation) commandLine() string {
+	quoted := make([]string, len(inv.argv))
+	for i, arg := range inv.argv {
+		quoted[i] = quoteArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quoteArg quotes arg for display the way a POSIX shell would need it.
+func quoteArg(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(arg, " \t\n'\"\\$`&|;<>()*?[]{}~#!") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// scriptLines reads the command's script for display. Missing or binary
+// files yield a short explanation instead of content.
+func scriptLines(command core.Command) []string {
+	if command.Script == "" {
+		return nil
+	}
+	file, err := os.Open(command.Script)
+	if err != nil {
+		return []string{"(cannot read " + filepath.Base(command.Script) + ": " + err.Error() + ")"}
+	}
+	defer file.Close()
+
+	buf := make([]byte, maxPreviewScriptBytes)
+	n, _ := file.Read(buf)
+	data := buf[:n]
+	if strings.ContainsRune(string(data), 0) {
+		return []string{"(binary file)"}
+	}
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = sanitizeOutputLine(line)
+	}
+	if n == maxPreviewScriptBytes {
+		lines = append(lines, "…")
+	}
+	return lines
+}
+
+// refreshLauncherPreview rebuilds the cached preview when the query or the
+// selected command changed since the last call.
+func (m *Model) refreshLauncherPreview() {
+	if !m.launcherPreview {
+		m.launcherPreviewCache = nil
+		m.launcherPreviewKey = ""
+		return
+	}
+	command, ok := m.selectedCommand()
+	key := m.launcherInput.Value() + "\x00" + command.ID
+	if m.launcherPreviewCache != nil && key == m.launcherPreviewKey {
+		return
+	}
+	m.launcherPreviewKey = key
+	if !ok {
+		m.launcherPreviewCache = &launcherview.Preview{}
+		return
+	}
+	m.launcherPreviewCache = m.buildLauncherPreview(command)
+}
+
+// buildLauncherPreview resolves command and reads its script from disk.
+func (m Model) buildLauncherPreview(command core.Command) *launcherview.Preview {
+	inv := m.resolveInvocation(command)
+	fields := inv.fields()
+	preview := &launcherview.Preview{
+		Fields: make([]launcherview.PreviewField, len(fields)),
+		Script: scriptLines(inv.command),
+	}
+	for i, field := range fields {
+		preview.Fields[i] = launcherview.PreviewField{Label: field.label, Value: field.value}
+	}
+	return preview
+}
@@ -0,0 +1,279 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"time"
+
+	"github.com/Noudea/glyph/internal/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickRunThreshold is how long a run must last before the auto pause policy
+// stops waiting for the user to read its output.
+const quickRunThreshold = 2 * time.Second
+
+const clearScreenSequence = "\x1b[H\x1b[2J"
+
+// stopGracePeriod is how long a process gets after each signal before the
+// next, stronger one is sent.
+const stopGracePeriod = 3 * time.Second
+
+var errCommandCanceled = errors.New("canceled")
+
+type commandTimeoutError struct {
+	after time.Duration
+}
+
+func (e *commandTimeoutError) Error() string {
+	return "timed out after " + e.after.String()
+}
+
+// stopProcess sends stopSignals in order, giving the process stopGracePeriod
+// to exit after each one. exited must be closed once the process is reaped.
+// With group, escalation goes on while anything in the group is left, such
+// as a background job that ignores SIGINT.
+func stopProcess(cmd *exec.Cmd, group bool, exited <-chan struct{}) {
+	for i, sig := range stopSignals {
+		if err := signalProcess(cmd, sig, group); err != nil {
+			return
+		}
+		if i == len(stopSignals)-1 {
+			return
+		}
+		if waitStopped(cmd, group, exited) {
+			return
+		}
+	}
+}
+
+// waitStopped reports whether the process, and with group its whole group,
+// exited within stopGracePeriod.
+func waitStopped(cmd *exec.Cmd, group bool, exited <-chan struct{}) bool {
+	deadline := time.After(stopGracePeriod)
+	select {
+	case <-exited:
+	case <-deadline:
+		return false
+	}
+	if !group {
+		return true
+	}
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for processGroupAlive(cmd) {
+		select {
+		case <-deadline:
+			return false
+		case <-tick.C:
+		}
+	}
+	return true
+}
+
+// trackedProcess is a process running outside tea.Exec, in its own process
+// group, that can time out or be canceled.
+type trackedProcess struct {
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	timedOut atomic.Bool
+	canceled atomic.Bool
+}
+
+// startTrackedProcess starts cmd and calls onExit from another goroutine
+// once it is reaped. Timeouts and cancellation are reported to onExit as
+// *commandTimeoutError and errCommandCanceled instead of the exit status.
+func startTrackedProcess(cmd *exec.Cmd, timeout time.Duration, onExit func(error)) (*trackedProcess, error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &trackedProcess{cmd: cmd, exited: make(chan struct{})}
+	if timeout > 0 {
+		go func() {
+			select {
+			case <-p.exited:
+			case <-time.After(timeout):
+				p.timedOut.Store(true)
+				stopProcess(cmd, true, p.exited)
+			}
+		}()
+	}
+	go func() {
+		err := cmd.Wait()
+		close(p.exited)
+		switch {
+		case p.timedOut.Load():
+			err = &commandTimeoutError{after: timeout}
+		case p.canceled.Load():
+			err = errCommandCanceled
+		}
+		onExit(err)
+	}()
+	return p, nil
+}
+
+// Cancel stops the process group with escalating signals.
+func (p *trackedProcess) Cancel() {
+	if p.canceled.Swap(true) {
+		return
+	}
+	go stopProcess(p.cmd, true, p.exited)
+}
+
+// Stop is Cancel that waits for the process group to go away, for when
+// Glyph is about to exit and a stop in the background would never finish.
+func (p *trackedProcess) Stop() {
+	if p.canceled.Swap(true) {
+		// A stop already under way is done within the escalation.
+		select {
+		case <-p.exited:
+		case <-time.After(stopGracePeriod * time.Duration(len(stopSignals))):
+		}
+		return
+	}
+	stopProcess(p.cmd, true, p.exited)
+	select {
+	case <-p.exited:
+	case <-time.After(stopGracePeriod):
+	}
+}
+
+// processEventBuffer bounds queued process events; output notifications
+// beyond it are dropped because the next redraw reads the buffer anyway.
+const processEventBuffer = 64
+
+// processOutputMsg asks for a redraw after a captured process wrote output.
+type processOutputMsg struct{}
+
+// waitForProcessEvent delivers the next event from background and captured
+// processes. The shell re-arms it after every event so a listener is always
+// pending.
+func (m *Model) waitForProcessEvent() tea.Cmd {
+	events := m.events
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// outputNotifier returns a non-blocking redraw trigger for output buffers.
+func (m *Model) outputNotifier() func() {
+	events := m.events
+	return func() {
+		select {
+		case events <- processOutputMsg{}:
+		default:
+		}
+	}
+}
+
+// execProcess adapts an exec.Cmd for tea.Exec. Clearing the screen and the
+// "press Enter" pause happen in Go, so the child needs no shell wrapper.
+type execProcess struct {
+	cmd     *exec.Cmd
+	after   core.AfterRun
+	timeout time.Duration
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func newExecProcess(cmd *exec.Cmd, command core.Command) *execProcess {
+	return &execProcess{cmd: cmd, after: command.After, timeout: command.Timeout}
+}
+
+func (p *execProcess) SetStdin(r io.Reader) {
+	p.stdin = r
+	if p.cmd.Stdin == nil {
+		p.cmd.Stdin = r
+	}
+}
+
+func (p *execProcess) SetStdout(w io.Writer) {
+	p.stdout = w
+	if p.cmd.Stdout == nil {
+		p.cmd.Stdout = w
+	}
+}
+
+func (p *execProcess) SetStderr(w io.Writer) {
+	if p.cmd.Stderr == nil {
+		p.cmd.Stderr = w
+	}
+}
+
+func (p *execProcess) Run() error {
+	out := p.stdout
+	if out == nil {
+		out = os.Stdout
+	}
+	if !p.after.KeepScreen {
+		fmt.Fprint(out, clearScreenSequence)
+	}
+
+	started := time.Now()
+	err := p.runWithTimeout()
+
+	if shouldPause(p.after.Pause, err, time.Since(started)) {
+		waitForEnter(p.stdin, out)
+	}
+	if !p.after.KeepScreen {
+		fmt.Fprint(out, clearScreenSequence)
+	}
+	return err
+}
+
+// runWithTimeout runs the process attached to the terminal. With a timeout
+// it gets its own process group, in the terminal's foreground, so the stop
+// signals reach everything it started.
+func (p *execProcess) runWithTimeout() error {
+	if p.timeout <= 0 {
+		return p.cmd.Run()
+	}
+	release := claimTerminal(p.cmd, p.stdin)
+	defer release()
+	if err := p.cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan struct{})
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- p.cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case err := <-waitErr:
+		return err
+	case <-time.After(p.timeout):
+		stopProcess(p.cmd, true, exited)
+		<-exited
+		return &commandTimeoutError{after: p.timeout}
+	}
+}
+
+func shouldPause(policy core.PausePolicy, err error, elapsed time.Duration) bool {
+	switch policy {
+	case core.PauseAlways:
+		return true
+	case core.PauseNever:
+		return false
+	case core.PauseOnFailure:
+		return err != nil
+	default:
+		return elapsed < quickRunThreshold
+	}
+}
+
+// waitForEnter prints the return prompt and blocks until a line is read.
+func waitForEnter(in io.Reader, out io.Writer) {
+	if in == nil {
+		in = os.Stdin
+	}
+	fmt.Fprint(out, "\n[glyph] Press Enter to return...")
+	_, _ = bufio.NewReader(in).ReadString('\n')
+}
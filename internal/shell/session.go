@@ -0,0 +1,61 @@
+package shell
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/Noudea/glyph/internal/core"
+)
+
+// sessionFile is what Glyph remembers between runs.
+type sessionFile struct {
+	OpenApps  []string `json:"openApps"`
+	ActiveApp string   `json:"activeApp,omitempty"`
+}
+
+func sessionPath(globalRoot string) string {
+	return filepath.Join(globalRoot, "session", "apps.json")
+}
+
+// loadSession fills the open apps of state from the last session. A
+// missing file leaves state as it is.
+func loadSession(path string, state *core.State) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var session sessionFile
+	if err := json.Unmarshal(data, &session); err != nil {
+		return err
+	}
+	state.OpenApps = session.OpenApps
+	state.ActiveApp = session.ActiveApp
+	return nil
+}
+
+func writeSession(path string, state *core.State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	session := sessionFile{OpenApps: state.OpenApps, ActiveApp: state.ActiveApp}
+	if session.OpenApps == nil {
+		session.OpenApps = []string{}
+	}
+	data := marshalForEdit(session, "", "  ") + "\n"
+	return os.WriteFile(path, []byte(data), 0o644)
+}
+
+// saveSession remembers the open apps for the next run.
+func (m *Model) saveSession() {
+	if m.sessionPath == "" {
+		return
+	}
+	if err := writeSession(m.sessionPath, m.state); err != nil {
+		m.err = "session: " + err.Error()
+	}
+}
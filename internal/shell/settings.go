@@ -0,0 +1,507 @@
+package shell
+
+import (
+	"strconv"
+	"strings"
+
+	formview "github.com/Noudea/glyph/internal/view/form"
+	settingsview "github.com/Noudea/glyph/internal/view/settings"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const commandSettingsOpen = "settings.open"
+
+// settingsState is the command and shortcut editor. It works on the raw
+// entries of one config file so disabled commands stay listed and edits
+// land on the right entry.
+type settingsState struct {
+	project   bool
+	path      string
+	entries   []commandConfig
+	shortcuts map[string][]string
+	cursor    int
+	deleting  bool
+	err       string
+
+	editing bool
+	form    settingsForm
+}
+
+const (
+	settingsFieldID = iota
+	settingsFieldLabel
+	settingsFieldRun
+	settingsFieldGroup
+	settingsFieldShortcut
+	settingsFieldCount
+)
+
+// settingsForm edits one entry. index is -1 for a new command.
+type settingsForm struct {
+	index  int
+	inputs [settingsFieldCount]textinput.Model
+	focus  int
+	// runLocked is set for entries defined by script, argv or steps; their
+	// run line is shown but not edited here.
+	runLocked bool
+	err       string
+}
+
+func (m *Model) openSettings() {
+	m.launcherInput.Blur()
+	m.settings.project = m.settings.project && m.projectConfigPath != ""
+	m.settings.editing = false
+	m.settings.deleting = false
+	m.settings.err = ""
+	m.loadSettingsEntries()
+	m.mode = ModeSettings
+}
+
+// loadSettingsEntries rereads the config file of the current scope.
+func (m *Model) loadSettingsEntries() {
+	s := &m.settings
+	s.entries = nil
+	s.shortcuts = nil
+	s.path = m.globalConfigPath
+	if s.project {
+		s.path = m.projectConfigPath
+	}
+	if s.path != "" {
+		config, err := loadConfig(s.path)
+		if err != nil {
+			s.err = err.Error()
+		} else {
+			s.entries = config.Commands
+			s.shortcuts, _ = decodeShortcutMap(config.Shortcuts)
+		}
+	}
+	if s.cursor >= len(s.entries) {
+		s.cursor = len(s.entries) - 1
+	}
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+}
+
+func (m *Model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.settings
+	if s.editing {
+		return m.updateSettingsForm(msg)
+	}
+	action := m.keyAction(scopeSettings, msg.String())
+	if s.deleting {
+		s.deleting = false
+		if action == "settings.confirm-delete" {
+			return m, m.deleteSettingsEntry()
+		}
+		return m, nil
+	}
+
+	s.err = ""
+	switch action {
+	case "settings.back":
+		m.openLauncher()
+	case "settings.up":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "settings.down":
+		if s.cursor < len(s.entries)-1 {
+			s.cursor++
+		}
+	case "settings.move-up":
+		return m, m.moveSettingsEntry(-1)
+	case "settings.move-down":
+		return m, m.moveSettingsEntry(1)
+	case "settings.scope":
+		m.setSettingsScope(!s.project)
+	case "settings.global":
+		m.setSettingsScope(false)
+	case "settings.project":
+		m.setSettingsScope(true)
+	case "settings.new":
+		m.openSettingsForm(-1)
+	case "settings.edit":
+		if len(s.entries) > 0 {
+			m.openSettingsForm(s.cursor)
+		}
+	case "settings.toggle":
+		return m, m.toggleSettingsEntry()
+	case "settings.delete":
+		if len(s.entries) > 0 {
+			s.deleting = true
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) setSettingsScope(project bool) {
+	if m.settings.project == project {
+		return
+	}
+	m.settings.project = project
+	m.settings.cursor = 0
+	m.loadSettingsEntries()
+}
+
+func (m *Model) selectedSettingsEntry() (commandConfig, bool) {
+	s := m.settings
+	if s.cursor < 0 || s.cursor >= len(s.entries) {
+		return commandConfig{}, false
+	}
+	return s.entries[s.cursor], true
+}
+
+// applySettingsEdit reloads config and the editor after a write, so the
+// palette and the list reflect the file on disk.
+func (m *Model) applySettingsEdit(notice string) tea.Cmd {
+	if err := m.reloadConfig(); err != nil {
+		m.err = err.Error()
+		m.settings.err = err.Error()
+	}
+	m.loadSettingsEntries()
+	return m.notify(notice)
+}
+
+func (m *Model) toggleSettingsEntry() tea.Cmd {
+	entry, ok := m.selectedSettingsEntry()
+	if !ok {
+		return nil
+	}
+	change := configMember{key: "enabled", value: false}
+	notice := "Disabled " + entry.ID
+	if !commandEnabled(entry.Enabled) {
+		change.value = nil
+		notice = "Enabled " + entry.ID
+	}
+	if err := updateCommandInConfig(m.settings.path, m.settings.cursor, []configMember{change}); err != nil {
+		m.settings.err = err.Error()
+		return nil
+	}
+	return m.applySettingsEdit(notice)
+}
+
+func (m *Model) deleteSettingsEntry() tea.Cmd {
+	entry, ok := m.selectedSettingsEntry()
+	if !ok {
+		return nil
+	}
+	if err := deleteCommandInConfig(m.settings.path, m.settings.cursor); err != nil {
+		m.settings.err = err.Error()
+		return nil
+	}
+	if !m.settingsIDTaken(entry.ID, m.settings.cursor) {
+		if err := removeShortcutInConfig(m.settings.path, entry.ID); err != nil {
+			m.settings.err = err.Error()
+			return nil
+		}
+	}
+	return m.applySettingsEdit("Deleted " + entry.ID)
+}
+
+func (m *Model) moveSettingsEntry(delta int) tea.Cmd {
+	s := &m.settings
+	target := s.cursor + delta
+	if len(s.entries) == 0 || target < 0 || target >= len(s.entries) {
+		return nil
+	}
+	if err := swapCommandsInConfig(s.path, s.cursor, target); err != nil {
+		s.err = err.Error()
+		return nil
+	}
+	s.cursor = target
+	if err := m.reloadConfig(); err != nil {
+		m.err = err.Error()
+	}
+	m.loadSettingsEntries()
+	return nil
+}
+
+// settingsIDTaken reports whether another entry of the file, other than
+// index, uses id.
+func (m Model) settingsIDTaken(id string, index int) bool {
+	for i, entry := range m.settings.entries {
+		if i != index && strings.TrimSpace(entry.ID) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) openSettingsForm(index int) {
+	var entry commandConfig
+	if index >= 0 {
+		entry = m.settings.entries[index]
+	}
+	runLocked := entry.Script != "" || len(entry.Argv) > 0 || len(entry.Steps) > 0
+	values := [settingsFieldCount]string{
+		entry.ID,
+		entry.Label,
+		entry.Run,
+		entry.Group,
+		strings.Join(m.settings.shortcuts[entry.ID], ", "),
+	}
+	placeholders := [settingsFieldCount]string{
+		"user.my-command",
+		"Label",
+		"shell command",
+		"optional section, e.g. build",
+		"optional, e.g. alt+b, ctrl+g s",
+	}
+
+	var inputs [settingsFieldCount]textinput.Model
+	for i := range inputs {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 256
+		input.Width = 48
+		input.Placeholder = placeholders[i]
+		input.SetValue(values[i])
+		inputs[i] = input
+	}
+	m.settings.form = settingsForm{
+		index:     index,
+		inputs:    inputs,
+		runLocked: runLocked,
+	}
+	m.settings.editing = true
+	m.focusSettingsField(settingsFieldID)
+}
+
+func (m *Model) updateSettingsForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := &m.settings.form
+	switch m.keyAction(scopeForm, msg.String()) {
+	case "form.cancel":
+		m.settings.editing = false
+		return m, nil
+	case "form.next":
+		m.focusSettingsField(form.focus + 1)
+		return m, nil
+	case "form.prev":
+		m.focusSettingsField(form.focus - 1)
+		return m, nil
+	case "form.submit":
+		return m, m.submitSettingsForm()
+	}
+
+	var cmd tea.Cmd
+	form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+	form.err = ""
+	return m, cmd
+}
+
+func (m *Model) focusSettingsField(index int) {
+	form := &m.settings.form
+	index = (index%settingsFieldCount + settingsFieldCount) % settingsFieldCount
+	if index == settingsFieldRun && form.runLocked {
+		if index > form.focus {
+			index++
+		} else {
+			index--
+		}
+	}
+	form.focus = index
+	for i := range form.inputs {
+		if i == form.focus {
+			form.inputs[i].Focus()
+		} else {
+			form.inputs[i].Blur()
+		}
+	}
+}
+
+// splitShortcuts parses the comma separated shortcut field.
+func splitShortcuts(value string) []string {
+	return normalizeShortcutKeys(strings.Split(value, ","))
+}
+
+// submitSettingsForm validates the entry with the loader's rules, then
+// writes only the fields the form owns so unknown keys survive.
+func (m *Model) submitSettingsForm() tea.Cmd {
+	s := &m.settings
+	form := &s.form
+	value := func(field int) string {
+		return strings.TrimSpace(form.inputs[field].Value())
+	}
+	id := value(settingsFieldID)
+	keys := splitShortcuts(form.inputs[settingsFieldShortcut].Value())
+
+	var entry commandConfig
+	oldID := ""
+	if form.index >= 0 {
+		entry = s.entries[form.index]
+		oldID = strings.TrimSpace(entry.ID)
+	}
+	entry.ID = id
+	entry.Label = value(settingsFieldLabel)
+	entry.Group = value(settingsFieldGroup)
+	if !form.runLocked {
+		entry.Run = value(settingsFieldRun)
+	}
+	candidate := entry
+	candidate.Enabled = nil
+	if _, _, err := parseCommandConfig(candidate, commandSourceGlobal, "", ""); err != nil {
+		form.err = err.Error()
+		return nil
+	}
+	if strings.ContainsAny(id, " \t") {
+		form.err = "command id cannot contain spaces"
+		return nil
+	}
+	if m.settingsIDTaken(id, form.index) {
+		form.err = "this config already has a command with id " + id
+		return nil
+	}
+	if err := m.checkSettingsShortcuts(keys, oldID, id); err != nil {
+		form.err = err.Error()
+		return nil
+	}
+
+	path := s.path
+	if form.index < 0 {
+		target, _, err := m.saveTargetPath(s.project)
+		if err != nil {
+			form.err = err.Error()
+			return nil
+		}
+		path = target
+		if entry.Label == "" {
+			entry.Label = id
+		}
+		entry = commandConfig{ID: id, Label: entry.Label, Run: entry.Run, Group: entry.Group}
+		if err := addCommandToConfig(path, entry); err != nil {
+			form.err = err.Error()
+			return nil
+		}
+	} else {
+		changes := []configMember{
+			{key: "id", value: id},
+			optionalMember("label", entry.Label),
+			optionalMember("group", entry.Group),
+		}
+		if !form.runLocked {
+			changes = append(changes, configMember{key: "run", value: entry.Run})
+		}
+		if err := updateCommandInConfig(path, form.index, changes); err != nil {
+			form.err = err.Error()
+			return nil
+		}
+	}
+
+	if oldID != "" && (oldID != id || len(keys) == 0) && len(s.shortcuts[oldID]) > 0 {
+		if err := removeShortcutInConfig(path, oldID); err != nil {
+			form.err = err.Error()
+			return nil
+		}
+	}
+	if len(keys) > 0 {
+		if err := setShortcutInConfig(path, id, keys); err != nil {
+			form.err = err.Error()
+			return nil
+		}
+	}
+
+	if form.index < 0 {
+		s.cursor = len(s.entries)
+	}
+	s.editing = false
+	return m.applySettingsEdit("Saved " + id)
+}
+
+// checkSettingsShortcuts applies the applyShortcuts rules to keys before
+// they are written.
+func (m Model) checkSettingsShortcuts(keys []string, oldID, id string) error {
+	for _, key := range keys {
+		if err := m.shortcutConflict(key, oldID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optionalMember removes key when value is empty.
+func optionalMember(key, value string) configMember {
+	if value == "" {
+		return configMember{key: key}
+	}
+	return configMember{key: key, value: value}
+}
+
+// entryDetail summarizes what an entry runs for the settings list.
+func entryDetail(entry commandConfig) string {
+	switch {
+	case len(entry.Steps) > 0:
+		return "chain · " + strconv.Itoa(len(entry.Steps)) + " steps"
+	case len(entry.Argv) > 0:
+		return "argv: " + invocation{argv: entry.Argv}.commandLine()
+	case entry.Script != "":
+		return "script: " + entry.Script
+	default:
+		return entry.Run
+	}
+}
+
+func (m Model) settingsViewState(height int) settingsview.ViewState {
+	s := m.settings
+	rows := make([]settingsview.Row, len(s.entries))
+	for i, entry := range s.entries {
+		rows[i] = settingsview.Row{
+			ID:       entry.ID,
+			Label:    entry.Label,
+			Detail:   entryDetail(entry),
+			Group:    entry.Group,
+			Shortcut: strings.Join(s.shortcuts[entry.ID], "/"),
+			Disabled: !commandEnabled(entry.Enabled),
+		}
+	}
+	return settingsview.ViewState{
+		Project:    s.project,
+		HasProject: m.projectConfigPath != "",
+		Path:       s.path,
+		Rows:       rows,
+		Cursor:     s.cursor,
+		Deleting:   s.deleting,
+		Err:        s.err,
+		Footer:     m.settingsFooter(),
+		Width:      m.width,
+		Height:     height,
+	}
+}
+
+func (m Model) settingsFormViewState(height int) formview.ViewState {
+	form := m.settings.form
+	labels := [settingsFieldCount]string{"id", "label", "run", "group", "shortcuts"}
+	fields := make([]formview.Field, 0, settingsFieldCount)
+	for i, input := range form.inputs {
+		field := formview.Field{
+			Label:   labels[i],
+			View:    input.View(),
+			Focused: form.focus == i,
+		}
+		if i == settingsFieldRun && form.runLocked {
+			field.View = entryDetail(m.settings.entries[form.index])
+			field.Hint = "edit in the config file"
+		}
+		if i == settingsFieldShortcut {
+			field.Hint = "comma separated"
+		}
+		fields = append(fields, field)
+	}
+	title := "New command"
+	if form.index >= 0 {
+		title = "Edit " + m.settings.entries[form.index].ID
+	}
+	scope := "global"
+	if m.settings.project {
+		scope = "project"
+	}
+	return formview.ViewState{
+		Title:  title + " · " + scope,
+		Fields: fields,
+		Err:    form.err,
+		Footer: m.formHint(),
+		Width:  m.width,
+		Height: height,
+	}
+}
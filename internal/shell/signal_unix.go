@@ -0,0 +1,71 @@
+//go:build !windows
+
+package shell
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// stopSignals is the escalation order used when stopping a process.
+var stopSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL}
+
+// setProcessGroup starts cmd in its own process group so children spawned
+// by a shell can be signaled together.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func signalProcess(cmd *exec.Cmd, sig os.Signal, group bool) error {
+	if cmd.Process == nil {
+		return os.ErrProcessDone
+	}
+	if group {
+		if s, ok := sig.(syscall.Signal); ok {
+			return syscall.Kill(-cmd.Process.Pid, s)
+		}
+	}
+	return cmd.Process.Signal(sig)
+}
+
+// processGroupAlive reports whether anything is left in cmd's group.
+func processGroupAlive(cmd *exec.Cmd) bool {
+	if cmd.Process == nil {
+		return false
+	}
+	return syscall.Kill(-cmd.Process.Pid, 0) != syscall.ESRCH
+}
+
+// claimTerminal starts cmd in its own process group so stopping it reaches
+// everything it spawned. When stdin is Glyph's terminal the group also
+// becomes the terminal's foreground group, so the command can still read
+// from it; the returned func hands the terminal back once cmd has exited.
+func claimTerminal(cmd *exec.Cmd, stdin io.Reader) func() {
+	setProcessGroup(cmd)
+	tty, ok := stdin.(*os.File)
+	if !ok {
+		return func() {}
+	}
+	fd := int(tty.Fd())
+	foreground, err := unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+	if err != nil {
+		return func() {}
+	}
+	cmd.SysProcAttr.Foreground = true
+	cmd.SysProcAttr.Ctty = fd
+	return func() {
+		// Glyph is a background group until this call, and changing the
+		// foreground group from there raises SIGTTOU.
+		signal.Ignore(syscall.SIGTTOU)
+		defer signal.Reset(syscall.SIGTTOU)
+		_ = unix.IoctlSetPointerInt(fd, unix.TIOCSPGRP, foreground)
+	}
+}
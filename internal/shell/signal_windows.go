@@ -0,0 +1,48 @@
+//go:build windows
+
+package shell
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// stopSignals is the escalation order used when stopping a process. Windows
+// cannot deliver SIGINT or SIGTERM to another process, so it kills directly.
+var stopSignals = []os.Signal{os.Kill}
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// signalProcess kills the process, and with group everything it started.
+func signalProcess(cmd *exec.Cmd, sig os.Signal, group bool) error {
+	if cmd.Process == nil {
+		return os.ErrProcessDone
+	}
+	if group {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		if err := kill.Run(); err == nil {
+			return nil
+		}
+	}
+	return cmd.Process.Kill()
+}
+
+// processGroupAlive is false because signalProcess kills the whole tree
+// at once.
+func processGroupAlive(cmd *exec.Cmd) bool {
+	return false
+}
+
+// claimTerminal leaves cmd in Glyph's console: a new process group would
+// stop Ctrl+C from reaching it. signalProcess stops its tree instead.
+func claimTerminal(cmd *exec.Cmd, stdin io.Reader) func() {
+	return func() {}
+}
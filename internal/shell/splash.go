@@ -19,7 +19,13 @@ func splashTickCmd() tea.Cmd {
 	})
 }
 
+// finishSplash shows the apps restored from the last session, or the
+// launcher when there are none.
 func (m *Model) finishSplash() {
 	m.splashFrame = 0
+	if m.activeModule() != nil {
+		m.mode = ModeMain
+		return
+	}
 	m.openLauncher()
 }
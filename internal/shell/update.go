@@ -3,34 +3,79 @@ package shell
 import (
 	"strings"
 
+	"github.com/Noudea/glyph/internal/core"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Any message may reload the config and with it the workspace.
+	return model, tea.Batch(cmd, m.syncWorkspace())
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		return m, nil
+		return m, m.broadcastApps(msg)
 	case splashTickMsg:
 		return m.updateSplashTick()
 	case commandFinishedMsg:
-		m.handleCommandFinished(msg)
+		if msg.Chain {
+			return m, m.handleChainStepFinished(msg)
+		}
+		return m, m.handleCommandFinished(msg)
+	case processOutputMsg:
+		return m, m.waitForProcessEvent()
+	case jobExitedMsg:
+		return m, tea.Batch(m.handleJobExited(msg), m.waitForProcessEvent())
+	case captureFinishedMsg:
+		return m, tea.Batch(m.handleCaptureFinished(msg), m.waitForProcessEvent())
+	case chordExpiredMsg:
+		if msg.seq == m.chordSeq {
+			m.chord = nil
+		}
 		return m, nil
+	case importPollMsg:
+		return m, m.handleImportPoll()
+	case noticeExpiredMsg:
+		if msg.seq == m.noticeSeq {
+			m.notice = ""
+		}
+		return m, nil
+	case core.NotifyMsg:
+		return m, m.notify(msg.Text)
+	case core.LaunchMsg:
+		return m, m.executeCommand(msg.CommandID)
+	case core.ExecMsg:
+		return m, m.execForModule(msg)
+	case appExecFinishedMsg:
+		return m, tea.Batch(m.handleAppExecFinished(msg), m.waitForProcessEvent())
 	case marketplaceListMsg, marketplaceInstallMsg, marketplaceUninstallMsg, marketplaceUpdateMsg:
 		return m.updateMarketplace(msg)
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 	}
-	return m, nil
+	// Anything else comes from an app's own commands.
+	return m, m.broadcastApps(msg)
 }
 
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-
-	switch key {
-	case "ctrl+c":
-		return m, tea.Quit
+	switch m.keyAction(m.keyScope(), msg.String()) {
+	case "global.quit":
+		return m, m.quit()
+	case "global.help":
+		if m.mode != ModeSplash {
+			m.helpOpen = !m.helpOpen
+			return m, nil
+		}
+	}
+	if m.helpOpen {
+		if msg.String() == "esc" {
+			m.helpOpen = false
+		}
+		return m, nil
 	}
 
 	switch m.mode {
@@ -42,11 +87,40 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.updateLauncher(msg)
 	case ModeMarketplace:
 		return m.updateMarketplace(msg)
+	case ModeChain:
+		return m.updateChain(msg)
+	case ModeJobs:
+		return m.updateJobs(msg)
+	case ModeOutput:
+		return m.updateCapture(msg)
+	case ModeConfirm:
+		return m.updateConfirm(msg)
+	case ModeSaveCommand:
+		return m.updateSaveCommand(msg)
+	case ModeSettings:
+		return m.updateSettings(msg)
+	case ModeKeys:
+		return m.updateKeysInspector(msg)
 	}
 
 	return m, nil
 }
 
+// quit stops background jobs, captures and plugins before leaving so none
+// outlive Glyph. What modules failed to save is kept for ExitErr, since the
+// screen is gone once the program ends.
+func (m *Model) quit() tea.Cmd {
+	m.stopAllProcesses()
+	m.exitErr = m.closeApps()
+	return tea.Quit
+}
+
+// ExitErr reports what went wrong while quitting, such as a module that
+// could not save.
+func (m *Model) ExitErr() error {
+	return m.exitErr
+}
+
 func (m *Model) updateSplash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Any key skips the splash and opens the launcher.
 	_ = msg
@@ -68,43 +142,96 @@ func (m *Model) updateSplashTick() (tea.Model, tea.Cmd) {
 
 func (m *Model) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
+	switch m.keyAction(scopeMain, key) {
+	case "main.next-app":
+		return m, m.cycleApp(1)
+	case "main.prev-app":
+		return m, m.cycleApp(-1)
+	case "main.close-app":
+		return m, m.closeApp()
+	}
 
-	if commandID, ok := m.resolveMainCommandIDForKey(key); ok {
+	// An open app gets every key that could be typed; the others go to
+	// shortcuts first, as in the palette.
+	app := m.activeModule() != nil
+	if app && len(m.chord) == 0 && !isLauncherShortcutCandidate(key) {
+		return m, m.updateApp(msg)
+	}
+	commandID, cmd, consumed := m.feedShortcutKey(key)
+	if commandID != "" {
 		return m, m.executeCommand(commandID)
 	}
-
-	return m, nil
+	if app && !consumed {
+		return m, tea.Batch(cmd, m.updateApp(msg))
+	}
+	return m, cmd
 }
 
 func (m *Model) updateLauncher(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var inputCmd tea.Cmd
-	m.launcherInput, inputCmd = m.launcherInput.Update(msg)
-	m.clampLauncherCursor()
-	var actionCmd tea.Cmd
+	defer m.refreshLauncherPreview()
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	// Keys after the start of a sequence belong to it, not to the query.
+	if isKey && len(m.chord) > 0 {
+		commandID, cmd, _ := m.feedShortcutKey(keyMsg.String())
+		return m, tea.Batch(cmd, m.runLauncherShortcut(commandID))
+	}
 
 	key := ""
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+	action := ""
+	if isKey {
 		key = keyMsg.String()
+		action = m.keyAction(scopeLauncher, key)
 	}
 
-	switch key {
-	case "esc":
+	// Action keys never type text, and alt+ keys are left to shortcuts.
+	var inputCmd tea.Cmd
+	if action == "" && !(isKey && keyMsg.Alt) {
+		before := m.launcherInput.Value()
+		m.launcherInput, inputCmd = m.launcherInput.Update(msg)
+		if m.launcherInput.Value() != before {
+			m.focusFirstCommand()
+		}
+	}
+	m.clampLauncherCursor()
+	var actionCmd tea.Cmd
+
+	switch action {
+	case "launcher.close":
 		m.launcherInput.Blur()
 		m.mode = ModeMain
 		return m, nil
-	case "up", "k":
+	case "launcher.preview":
+		m.launcherPreview = !m.launcherPreview
+	case "launcher.fold":
+		m.toggleLauncherSection()
+	case "launcher.save":
+		if command, ok := m.selectedCommand(); ok && command.Source == commandSourceAdhoc {
+			m.openSaveCommand(command.Run)
+			return m, inputCmd
+		}
+	case "launcher.next-section":
+		m.jumpLauncherSection(1)
+	case "launcher.prev-section":
+		m.jumpLauncherSection(-1)
+	case "launcher.up":
 		if m.launcherCursor > 0 {
 			m.launcherCursor--
 		}
-	case "down", "j":
-		if m.launcherCursor < len(m.filteredCommands())-1 {
+	case "launcher.down":
+		if m.launcherCursor < len(m.launcherRows())-1 {
 			m.launcherCursor++
 		}
-	case "enter":
-		cmds := m.filteredCommands()
-		if len(cmds) > 0 && m.launcherCursor >= 0 && m.launcherCursor < len(cmds) {
-			selected := cmds[m.launcherCursor]
-			actionCmd = m.executeCommand(selected.ID)
+	case "launcher.run":
+		row, ok := m.selectedLauncherRow()
+		if ok && row.header {
+			m.toggleLauncherSection()
+			return m, inputCmd
+		}
+		switch {
+		case ok && row.command.Source == commandSourceAdhoc:
+			actionCmd = m.runAdhoc(row.command)
+		case ok:
+			actionCmd = m.executeCommand(row.command.ID)
 		}
 		m.launcherInput.Blur()
 		if m.mode == ModeLauncher {
@@ -112,24 +239,32 @@ func (m *Model) updateLauncher(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	default:
 		if isLauncherShortcutCandidate(key) {
-			if commandID, ok := m.resolveMainCommandIDForKey(key); ok {
-				if commandID == commandLauncherOpen {
-					m.launcherInput.Blur()
-					m.mode = ModeMain
-					return m, nil
-				}
-				actionCmd = m.executeCommand(commandID)
-				m.launcherInput.Blur()
-				if m.mode == ModeLauncher {
-					m.mode = ModeMain
-				}
-			}
+			commandID, cmd, _ := m.feedShortcutKey(key)
+			actionCmd = tea.Batch(cmd, m.runLauncherShortcut(commandID))
 		}
 	}
 
 	return m, tea.Batch(inputCmd, actionCmd)
 }
 
+// runLauncherShortcut runs a command bound to a shortcut pressed in the
+// launcher; the launcher's own shortcut closes it.
+func (m *Model) runLauncherShortcut(commandID string) tea.Cmd {
+	if commandID == "" {
+		return nil
+	}
+	m.launcherInput.Blur()
+	if commandID == commandLauncherOpen {
+		m.mode = ModeMain
+		return nil
+	}
+	cmd := m.executeCommand(commandID)
+	if m.mode == ModeLauncher {
+		m.mode = ModeMain
+	}
+	return cmd
+}
+
 func isLauncherShortcutCandidate(key string) bool {
 	if key == "" {
 		return false
@@ -149,8 +284,8 @@ func isLauncherShortcutCandidate(key string) bool {
 }
 
 func (m *Model) clampLauncherCursor() {
-	cmds := m.filteredCommands()
-	if len(cmds) == 0 {
+	rows := m.launcherRows()
+	if len(rows) == 0 {
 		m.launcherCursor = 0
 		return
 	}
@@ -158,7 +293,7 @@ func (m *Model) clampLauncherCursor() {
 		m.launcherCursor = 0
 		return
 	}
-	if m.launcherCursor >= len(cmds) {
-		m.launcherCursor = len(cmds) - 1
+	if m.launcherCursor >= len(rows) {
+		m.launcherCursor = len(rows) - 1
 	}
 }
@@ -3,10 +3,19 @@ package shell
 import (
 	"strings"
 
+	chainview "github.com/Noudea/glyph/internal/view/chain"
+	confirmview "github.com/Noudea/glyph/internal/view/confirm"
+	formview "github.com/Noudea/glyph/internal/view/form"
+	helpview "github.com/Noudea/glyph/internal/view/help"
 	hintbarview "github.com/Noudea/glyph/internal/view/hintbar"
+	jobsview "github.com/Noudea/glyph/internal/view/jobs"
+	keysview "github.com/Noudea/glyph/internal/view/keys"
 	launcherview "github.com/Noudea/glyph/internal/view/launcher"
 	marketplaceview "github.com/Noudea/glyph/internal/view/marketplace"
+	outputview "github.com/Noudea/glyph/internal/view/output"
+	settingsview "github.com/Noudea/glyph/internal/view/settings"
 	splashview "github.com/Noudea/glyph/internal/view/splash"
+	topbarview "github.com/Noudea/glyph/internal/view/topbar"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -35,6 +44,9 @@ func (m *Model) View() string {
 }
 
 func (m *Model) renderContent(contentHeight int) string {
+	if m.helpOpen && m.mode != ModeSplash {
+		return helpview.Render(m.helpViewState(contentHeight))
+	}
 	switch m.mode {
 	case ModeSplash:
 		return splashview.Render(splashview.ViewState{
@@ -43,12 +55,23 @@ func (m *Model) renderContent(contentHeight int) string {
 			Height: contentHeight,
 		})
 	case ModeLauncher:
+		rows, total := m.launcherViewRows()
 		return launcherview.Render(launcherview.ViewState{
 			InputView: m.launcherInput.View(),
-			Commands:  m.filteredCommands(),
+			Rows:      rows,
+			Total:     total,
+			Help:      m.launcherHelpState(),
 			Cursor:    m.launcherCursor,
-			Width:     m.width,
-			Height:    contentHeight,
+			Preview:   m.launcherPreviewCache,
+			Footer: m.keyHints(
+				"launcher.run", "cast",
+				"launcher.next-section", "next group",
+				"launcher.fold", "fold",
+				"launcher.preview", "preview",
+				"launcher.close", "close",
+			),
+			Width:  m.width,
+			Height: contentHeight,
 		})
 	case ModeMarketplace:
 		entries := make([]marketplaceview.Entry, len(m.marketplace.entries))
@@ -60,6 +83,7 @@ func (m *Model) renderContent(contentHeight int) string {
 				InstalledProject: e.InstalledProject,
 				HasUpdateGlobal:  e.HasUpdateGlobal,
 				HasUpdateProject: e.HasUpdateProject,
+				Suggestions:      m.shortcutSuggestions(e.Remote),
 			}
 		}
 		return marketplaceview.Render(marketplaceview.ViewState{
@@ -70,9 +94,52 @@ func (m *Model) renderContent(contentHeight int) string {
 			Installing:     m.marketplace.installing,
 			ConfirmInstall: m.marketplace.confirmInstall,
 			HasProject:     m.resolveProjectRoot() != "",
-			Width:          m.width,
-			Height:         contentHeight,
+			Keys: marketplaceview.Keys{
+				Install:   m.firstKey("marketplace.install"),
+				Uninstall: m.firstKey("marketplace.uninstall"),
+				Update:    m.firstKey("marketplace.update"),
+				Back:      m.firstKey("marketplace.back"),
+				Global:    m.firstKey("install.global"),
+				Project:   m.firstKey("install.project"),
+				Cancel:    m.firstKey("install.cancel"),
+			},
+			Width:  m.width,
+			Height: contentHeight,
+		})
+	case ModeChain:
+		steps := make([]chainview.Step, len(m.chain.steps))
+		for i, step := range m.chain.steps {
+			steps[i] = chainview.Step{
+				Label:  step.command.Label,
+				Status: string(step.status),
+			}
+			if step.err != nil {
+				steps[i].Detail = formatCommandExecError(step.err)
+			}
+		}
+		return chainview.Render(chainview.ViewState{
+			Title:  m.chain.command.Label,
+			Steps:  steps,
+			Done:   m.chain.done,
+			Footer: m.chainFooter(),
+			Width:  m.width,
+			Height: contentHeight,
 		})
+	case ModeSaveCommand:
+		return formview.Render(m.saveCommandViewState(contentHeight))
+	case ModeSettings:
+		if m.settings.editing {
+			return formview.Render(m.settingsFormViewState(contentHeight))
+		}
+		return settingsview.Render(m.settingsViewState(contentHeight))
+	case ModeKeys:
+		return keysview.Render(m.keysInspectorViewState(contentHeight))
+	case ModeConfirm:
+		return confirmview.Render(m.confirmViewState(contentHeight))
+	case ModeOutput:
+		return outputview.Render(m.captureViewState(contentHeight))
+	case ModeJobs:
+		return jobsview.Render(m.jobsViewState(contentHeight))
 	case ModeMain:
 		fallthrough
 	default:
@@ -81,6 +148,18 @@ func (m *Model) renderContent(contentHeight int) string {
 }
 
 func (m *Model) renderMain(height int) string {
+	topbar := topbarview.Render(m.topbarViewState())
+	bodyHeight := height
+	if height > 0 {
+		bodyHeight = max(height-topbarview.Height(m.width), 1)
+	}
+	if module := m.activeModule(); module != nil {
+		return lipgloss.JoinVertical(lipgloss.Left, topbar, module.View(m.width, bodyHeight))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, topbar, m.renderWelcome(bodyHeight))
+}
+
+func (m *Model) renderWelcome(height int) string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#FF9F68"))
@@ -93,6 +172,13 @@ func (m *Model) renderMain(height int) string {
 		mutedStyle.Render("Press " + m.shortcutsHint(commandLauncherOpen, "ctrl+p/ctrl+k/alt+p") + " to open the command palette"),
 		mutedStyle.Render("Run commands in current terminal · current folder"),
 	}
+	if apps := m.appCommands(); len(apps) > 0 {
+		titles := make([]string, len(apps))
+		for i, app := range apps {
+			titles[i] = app.Label
+		}
+		lines = append(lines, mutedStyle.Render("Apps open from the palette too: "+strings.Join(titles, ", ")))
+	}
 
 	content := strings.Join(lines, "\n")
 	if m.width > 0 && height > 0 {
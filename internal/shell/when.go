@@ -0,0 +1,157 @@
+package shell
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// whenConfig limits where a command or spellbook is offered. Every field
+// that is set must match; within a list, any entry may match.
+type whenConfig struct {
+	// Exists holds file names or glob patterns looked up in the start folder
+	// and its parents, up to the repository root. Outside a repository the
+	// search stops at the project root.
+	Exists stringList `json:"exists,omitempty"`
+	// Git requires being inside (true) or outside (false) a git worktree.
+	Git *bool `json:"git,omitempty"`
+	// Env holds NAME (set and non-empty) or NAME=value entries.
+	Env stringList `json:"env,omitempty"`
+	// OS holds GOOS values; "macos" is accepted for darwin.
+	OS stringList `json:"os,omitempty"`
+	// Path holds binaries that must be found on PATH.
+	Path stringList `json:"path,omitempty"`
+}
+
+func decodeWhen(raw json.RawMessage) (*whenConfig, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var when whenConfig
+	if err := json.Unmarshal(raw, &when); err != nil {
+		return nil, err
+	}
+	return &when, nil
+}
+
+// whenContext evaluates conditions against the start folder. Results are
+// memoized because many commands share the same checks.
+type whenContext struct {
+	dirs   []string
+	inGit  bool
+	exists map[string]bool
+	onPath map[string]bool
+}
+
+// newWhenContext searches from startDir up to the repository root. Without
+// one it stops at projectDir, the folder holding .glyph, or at startDir
+// itself when projectDir is empty.
+func newWhenContext(startDir, projectDir string) *whenContext {
+	ctx := &whenContext{
+		exists: make(map[string]bool),
+		onPath: make(map[string]bool),
+	}
+	current := filepath.Clean(startDir)
+	for {
+		ctx.dirs = append(ctx.dirs, current)
+		if _, err := os.Lstat(filepath.Join(current, ".git")); err == nil {
+			ctx.inGit = true
+			return ctx
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	keep := 1
+	if projectDir != "" {
+		projectDir = filepath.Clean(projectDir)
+		for i, dir := range ctx.dirs {
+			if dir == projectDir {
+				keep = i + 1
+				break
+			}
+		}
+	}
+	ctx.dirs = ctx.dirs[:keep]
+	return ctx
+}
+
+func (c *whenContext) matches(when *whenConfig) bool {
+	if when == nil {
+		return true
+	}
+	if when.Git != nil && *when.Git != c.inGit {
+		return false
+	}
+	return matchAny(when.Exists, c.fileExists) &&
+		matchAny(when.Env, envMatches) &&
+		matchAny(when.OS, osMatches) &&
+		matchAny(when.Path, c.binaryOnPath)
+}
+
+func matchAny(values []string, match func(string) bool) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" && match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *whenContext) fileExists(pattern string) bool {
+	if found, ok := c.exists[pattern]; ok {
+		return found
+	}
+	found := false
+	if filepath.IsAbs(pattern) {
+		found = globMatches(pattern)
+	} else {
+		for _, dir := range c.dirs {
+			if globMatches(filepath.Join(dir, pattern)) {
+				found = true
+				break
+			}
+		}
+	}
+	c.exists[pattern] = found
+	return found
+}
+
+func globMatches(pattern string) bool {
+	matches, err := filepath.Glob(pattern)
+	return err == nil && len(matches) > 0
+}
+
+func (c *whenContext) binaryOnPath(name string) bool {
+	if found, ok := c.onPath[name]; ok {
+		return found
+	}
+	_, err := exec.LookPath(name)
+	c.onPath[name] = err == nil
+	return err == nil
+}
+
+func envMatches(entry string) bool {
+	if name, want, ok := strings.Cut(entry, "="); ok {
+		value, set := os.LookupEnv(name)
+		return set && value == want
+	}
+	return os.Getenv(entry) != ""
+}
+
+func osMatches(name string) bool {
+	name = strings.ToLower(name)
+	if name == "macos" || name == "mac" {
+		name = "darwin"
+	}
+	return name == runtime.GOOS
+}
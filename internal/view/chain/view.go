@@ -0,0 +1,158 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Step is one row of the chain progress view.
+type Step struct {
+	Label  string
+	Status string // pending, running, ok, failed or skipped
+	Detail string
+}
+
+type ViewState struct {
+	Title  string
+	Steps  []Step
+	Done   bool
+	Footer string // shown once the chain is done
+	Width  int
+	Height int
+}
+
+type chainStyles struct {
+	title   lipgloss.Style
+	count   lipgloss.Style
+	muted   lipgloss.Style
+	row     lipgloss.Style
+	ok      lipgloss.Style
+	failed  lipgloss.Style
+	running lipgloss.Style
+	panel   lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	panel := renderPanel(state)
+	if state.Width > 0 && state.Height > 0 {
+		panel = lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func renderPanel(state ViewState) string {
+	styles := newChainStyles()
+	contentWidth := resolvePanelWidth(state.Width) - 4
+	if contentWidth < 24 {
+		contentWidth = 24
+	}
+
+	finished := 0
+	for _, step := range state.Steps {
+		if step.Status == "ok" || step.Status == "failed" || step.Status == "skipped" {
+			finished++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(joinColumns(
+		styles.title.Render("✦ "+state.Title),
+		styles.count.Render(fmt.Sprintf("%d/%d steps", finished, len(state.Steps))),
+		contentWidth,
+	))
+	b.WriteString("\n")
+	b.WriteString(styles.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+
+	for i, step := range state.Steps {
+		icon, style := statusIcon(step.Status, styles)
+		line := fmt.Sprintf("%s %d. %s", style.Render(icon), i+1, step.Label)
+		if step.Detail != "" {
+			line += "  " + styles.failed.Render(step.Detail)
+		}
+		b.WriteString(styles.row.Width(contentWidth).Render(ansi.Truncate(line, contentWidth, "…")))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(styles.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+	footer := "running…"
+	if state.Done {
+		footer = state.Footer
+	}
+	b.WriteString(styles.muted.Width(contentWidth).Render(footer))
+
+	return styles.panel.Render(b.String())
+}
+
+func statusIcon(status string, styles chainStyles) (string, lipgloss.Style) {
+	switch status {
+	case "ok":
+		return "✓", styles.ok
+	case "failed":
+		return "✗", styles.failed
+	case "running":
+		return "▸", styles.running
+	case "skipped":
+		return "–", styles.muted
+	default:
+		return "·", styles.muted
+	}
+}
+
+func newChainStyles() chainStyles {
+	return chainStyles{
+		title:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		count:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#9AA3B8")),
+		muted:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		row:     lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")),
+		ok:      lipgloss.NewStyle().Foreground(lipgloss.Color("#A8E6CF")).Bold(true),
+		failed:  lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true),
+		running: lipgloss.NewStyle().Foreground(lipgloss.Color("#FFCF92")).Bold(true),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 1),
+	}
+}
+
+func resolvePanelWidth(screenWidth int) int {
+	const (
+		defaultWidth = 72
+		minWidth     = 40
+	)
+	if screenWidth <= 0 {
+		return defaultWidth
+	}
+	max := screenWidth - 4
+	if max < minWidth {
+		return max
+	}
+	if defaultWidth > max {
+		return max
+	}
+	return defaultWidth
+}
+
+func joinColumns(left, right string, width int) string {
+	if width < 1 {
+		return left
+	}
+	if right == "" {
+		return ansi.Truncate(left, width, "…")
+	}
+	rightWidth := lipgloss.Width(right)
+	maxLeft := width - rightWidth - 1
+	if maxLeft < 1 {
+		return ansi.Truncate(left+" "+right, width, "…")
+	}
+	left = ansi.Truncate(left, maxLeft, "…")
+	space := width - lipgloss.Width(left) - rightWidth
+	if space < 1 {
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
@@ -0,0 +1,84 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type ViewState struct {
+	Label     string
+	ID        string
+	Danger    string // "", low, medium or high
+	TypeName  bool   // require typing ID instead of y/n
+	InputView string
+	Mismatch  bool
+	Width     int
+	Height    int
+}
+
+type confirmStyles struct {
+	title  lipgloss.Style
+	danger lipgloss.Style
+	muted  lipgloss.Style
+	key    lipgloss.Style
+	row    lipgloss.Style
+	id     lipgloss.Style
+	panel  lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	s := newConfirmStyles(state.Danger)
+
+	lines := []string{s.title.Render("✦ Run " + state.Label + "?")}
+	switch state.Danger {
+	case "high":
+		lines = append(lines, s.danger.Render("‼ This command is destructive and cannot be undone."))
+	case "medium":
+		lines = append(lines, s.danger.Render("⚠ This command changes state."))
+	}
+	lines = append(lines, "")
+
+	if state.TypeName {
+		lines = append(lines,
+			s.muted.Render("Type ")+s.id.Render(state.ID)+s.muted.Render(" to confirm:"),
+			state.InputView,
+		)
+		if state.Mismatch {
+			lines = append(lines, s.danger.Render("That does not match the command ID."))
+		}
+		lines = append(lines, "", s.key.Render("enter")+s.muted.Render(" confirm · ")+s.key.Render("esc")+s.muted.Render(" cancel"))
+	} else {
+		lines = append(lines,
+			"  "+s.key.Render("y")+s.row.Render("  run it"),
+			"  "+s.key.Render("n")+s.row.Render("  cancel"),
+		)
+	}
+
+	panel := s.panel.Render(strings.Join(lines, "\n"))
+	if state.Width > 0 && state.Height > 0 {
+		return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func newConfirmStyles(danger string) confirmStyles {
+	border := lipgloss.Color("#5C6475")
+	accent := lipgloss.Color("#FFB86C")
+	if danger == "high" {
+		border = lipgloss.Color("#FF6B6B")
+		accent = lipgloss.Color("#FF6B6B")
+	}
+	return confirmStyles{
+		title:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		danger: lipgloss.NewStyle().Foreground(accent).Bold(true),
+		muted:  lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		key:    lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F68")).Bold(true),
+		row:    lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")),
+		id:     lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")).Bold(true),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(border).
+			Padding(0, 2),
+	}
+}
@@ -0,0 +1,79 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Field is one labeled line of the form. View is the rendered input or
+// value.
+type Field struct {
+	Label   string
+	View    string
+	Hint    string
+	Focused bool
+}
+
+type ViewState struct {
+	Title  string
+	Fields []Field
+	Err    string
+	Footer string
+	Width  int
+	Height int
+}
+
+type formStyles struct {
+	title   lipgloss.Style
+	label   lipgloss.Style
+	focused lipgloss.Style
+	hint    lipgloss.Style
+	err     lipgloss.Style
+	muted   lipgloss.Style
+	panel   lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	s := newFormStyles()
+
+	lines := []string{s.title.Render("✦ " + state.Title), ""}
+	for _, field := range state.Fields {
+		label := s.label.Render(field.Label)
+		if field.Focused {
+			label = s.focused.Render(field.Label)
+		}
+		line := label + " " + field.View
+		if field.Hint != "" {
+			line += "  " + s.hint.Render(field.Hint)
+		}
+		lines = append(lines, line)
+	}
+	if state.Err != "" {
+		lines = append(lines, "", s.err.Render(state.Err))
+	}
+	if state.Footer != "" {
+		lines = append(lines, "", s.muted.Render(state.Footer))
+	}
+
+	panel := s.panel.Render(strings.Join(lines, "\n"))
+	if state.Width > 0 && state.Height > 0 {
+		return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func newFormStyles() formStyles {
+	return formStyles{
+		title:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		label:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")).Width(10),
+		focused: lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F68")).Bold(true).Width(10),
+		hint:    lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")),
+		err:     lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true),
+		muted:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 2),
+	}
+}
@@ -0,0 +1,105 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Entry is one binding: the keys and what they do.
+type Entry struct {
+	Keys string
+	Text string
+}
+
+// Section groups the bindings of one mode.
+type Section struct {
+	Title   string
+	Entries []Entry
+}
+
+type ViewState struct {
+	Sections []Section
+	Footer   string
+	Width    int
+	Height   int
+}
+
+type helpStyles struct {
+	title   lipgloss.Style
+	section lipgloss.Style
+	keys    lipgloss.Style
+	text    lipgloss.Style
+	muted   lipgloss.Style
+	panel   lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	s := newHelpStyles()
+	width := resolvePanelWidth(state.Width) - 6
+
+	keyWidth := 0
+	for _, section := range state.Sections {
+		for _, entry := range section.Entries {
+			keyWidth = max(keyWidth, lipgloss.Width(entry.Keys))
+		}
+	}
+	keyWidth = min(keyWidth, width/2)
+
+	lines := []string{s.title.Render("✦ Key bindings")}
+	for _, section := range state.Sections {
+		if len(section.Entries) == 0 {
+			continue
+		}
+		lines = append(lines, "", s.section.Render(section.Title))
+		for _, entry := range section.Entries {
+			keys := s.keys.Width(keyWidth).Render(ansi.Truncate(entry.Keys, keyWidth, "…"))
+			lines = append(lines, keys+"  "+s.text.Render(ansi.Truncate(entry.Text, width-keyWidth-2, "…")))
+		}
+	}
+	if state.Height > 0 && len(lines) > state.Height-4 {
+		lines = append(lines[:max(state.Height-5, 1)], s.muted.Render("…"))
+	}
+	if state.Footer != "" {
+		lines = append(lines, "", s.muted.Render(state.Footer))
+	}
+
+	panel := s.panel.Render(strings.Join(lines, "\n"))
+	if state.Width > 0 && state.Height > 0 {
+		return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func newHelpStyles() helpStyles {
+	return helpStyles{
+		title:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		section: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD9A0")),
+		keys:    lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F68")),
+		text:    lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")),
+		muted:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 2),
+	}
+}
+
+func resolvePanelWidth(screenWidth int) int {
+	const (
+		defaultWidth = 80
+		minWidth     = 40
+	)
+	if screenWidth <= 0 {
+		return defaultWidth
+	}
+	max := screenWidth - 4
+	if max < minWidth {
+		return max
+	}
+	if defaultWidth > max {
+		return max
+	}
+	return defaultWidth
+}
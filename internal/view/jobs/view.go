@@ -0,0 +1,223 @@
+package view
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Job is one row of the background jobs list.
+type Job struct {
+	Label    string
+	Status   string // running, exited, failed or stopped
+	Duration string
+	Detail   string
+}
+
+type ViewState struct {
+	Jobs   []Job
+	Cursor int
+	Output []string // tail of the selected job's output
+	Footer string
+	Width  int
+	Height int
+}
+
+type jobStyles struct {
+	title     lipgloss.Style
+	count     lipgloss.Style
+	muted     lipgloss.Style
+	row       lipgloss.Style
+	rowActive lipgloss.Style
+	running   lipgloss.Style
+	ok        lipgloss.Style
+	failed    lipgloss.Style
+	divider   lipgloss.Style
+	panel     lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	panel := renderPanel(state)
+	if state.Width > 0 && state.Height > 0 {
+		panel = lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+// OutputRows returns how many output lines fit for a screen height.
+func OutputRows(height int) int {
+	rows := 16
+	if height > 0 {
+		rows = height - 8
+	}
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+func renderPanel(state ViewState) string {
+	s := newJobStyles()
+	contentWidth := resolvePanelWidth(state.Width) - 4
+	if contentWidth < 40 {
+		contentWidth = 40
+	}
+
+	running := 0
+	for _, j := range state.Jobs {
+		if j.Status == "running" {
+			running++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(joinColumns(
+		s.title.Render("✦ Background Jobs"),
+		s.count.Render(strconv.Itoa(running)+" running · "+strconv.Itoa(len(state.Jobs))+" total"),
+		contentWidth,
+	))
+	b.WriteString("\n")
+	b.WriteString(s.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+
+	if len(state.Jobs) == 0 {
+		b.WriteString(s.muted.Width(contentWidth).Render("No background jobs. Set \"background\": true on a command to run it here."))
+		b.WriteString("\n")
+	} else {
+		rows := OutputRows(state.Height)
+		leftWidth := contentWidth * 35 / 100
+		if leftWidth < 20 {
+			leftWidth = 20
+		}
+		rightWidth := contentWidth - leftWidth - 1
+
+		left := renderJobList(state, leftWidth, s)
+		right := renderOutput(state.Output, rightWidth, rows, s)
+
+		leftLines := strings.Count(left, "\n") + 1
+		rightLines := strings.Count(right, "\n") + 1
+		maxLines := leftLines
+		if rightLines > maxLines {
+			maxLines = rightLines
+		}
+		divider := strings.TrimRight(strings.Repeat(s.divider.Render("│")+"\n", maxLines), "\n")
+
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(leftWidth).Render(left),
+			divider,
+			lipgloss.NewStyle().Width(rightWidth).PaddingLeft(1).Render(right),
+		))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(s.muted.Width(contentWidth).Render(state.Footer))
+	return s.panel.Render(b.String())
+}
+
+func renderJobList(state ViewState, width int, s jobStyles) string {
+	lines := make([]string, 0, len(state.Jobs))
+	for i, j := range state.Jobs {
+		active := i == state.Cursor
+		icon, iconStyle := statusIcon(j.Status, s)
+		prefix := "  "
+		if active {
+			prefix = "✦ "
+		}
+		left := prefix + iconStyle.Render(icon) + " " + j.Label
+		row := joinColumns(left, j.Duration, width)
+		if active {
+			lines = append(lines, s.rowActive.Width(width).Render(ansi.Strip(row)))
+		} else {
+			lines = append(lines, s.row.Width(width).Render(row))
+		}
+		if active && j.Detail != "" {
+			lines = append(lines, s.failed.Render(ansi.Truncate("  "+j.Detail, width, "…")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderOutput(output []string, width, rows int, s jobStyles) string {
+	if len(output) == 0 {
+		return s.muted.Render("no output yet")
+	}
+	if len(output) > rows {
+		output = output[len(output)-rows:]
+	}
+	lines := make([]string, len(output))
+	for i, line := range output {
+		lines[i] = ansi.Truncate(line, width-1, "…")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func statusIcon(status string, s jobStyles) (string, lipgloss.Style) {
+	switch status {
+	case "running":
+		return "●", s.running
+	case "exited":
+		return "✓", s.ok
+	case "failed":
+		return "✗", s.failed
+	default:
+		return "■", s.muted
+	}
+}
+
+func newJobStyles() jobStyles {
+	return jobStyles{
+		title:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		count:     lipgloss.NewStyle().Foreground(lipgloss.Color("#9AA3B8")),
+		muted:     lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		row:       lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")),
+		rowActive: lipgloss.NewStyle().Foreground(lipgloss.Color("#2F1E0C")).Background(lipgloss.Color("#FFD9A0")).Bold(true),
+		running:   lipgloss.NewStyle().Foreground(lipgloss.Color("#FFCF92")).Bold(true),
+		ok:        lipgloss.NewStyle().Foreground(lipgloss.Color("#A8E6CF")).Bold(true),
+		failed:    lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")),
+		divider:   lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 1),
+	}
+}
+
+func resolvePanelWidth(screenWidth int) int {
+	const (
+		defaultWidth = 120
+		minWidth     = 40
+	)
+	if screenWidth <= 0 {
+		return defaultWidth
+	}
+	max := screenWidth - 4
+	if max < minWidth {
+		return max
+	}
+	if defaultWidth > max {
+		return max
+	}
+	return defaultWidth
+}
+
+func joinColumns(left, right string, width int) string {
+	if width < 1 {
+		return left
+	}
+	if right == "" {
+		return ansi.Truncate(left, width, "…")
+	}
+	rightWidth := lipgloss.Width(right)
+	maxLeft := width - rightWidth - 1
+	if maxLeft < 1 {
+		return ansi.Truncate(left+" "+right, width, "…")
+	}
+	left = ansi.Truncate(left, maxLeft, "…")
+	space := width - lipgloss.Width(left) - rightWidth
+	if space < 1 {
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
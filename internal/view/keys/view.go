@@ -0,0 +1,207 @@
+package view
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Row is one binding: a key, what it triggers and where it came from.
+type Row struct {
+	Key     string
+	Raw     string // the key as written in config, when it differs
+	Target  string
+	Scope   string
+	Origin  string
+	Dropped string // why the binding is not in effect
+}
+
+type ViewState struct {
+	Rows        []Row
+	Cursor      int
+	Dropped     int
+	DroppedOnly bool
+	Footer      string
+	Width       int
+	Height      int
+}
+
+type keysStyles struct {
+	title     lipgloss.Style
+	count     lipgloss.Style
+	muted     lipgloss.Style
+	hint      lipgloss.Style
+	row       lipgloss.Style
+	rowActive lipgloss.Style
+	dropped   lipgloss.Style
+	err       lipgloss.Style
+	panel     lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	panel := renderPanel(state)
+	if state.Width > 0 && state.Height > 0 {
+		panel = lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func renderPanel(state ViewState) string {
+	s := newKeysStyles()
+	contentWidth := resolvePanelWidth(state.Width) - 4
+	if contentWidth < 40 {
+		contentWidth = 40
+	}
+
+	count := strconv.Itoa(state.Dropped) + " dropped"
+	if state.DroppedOnly {
+		count = "showing dropped only · " + count
+	}
+
+	var b strings.Builder
+	b.WriteString(joinColumns(s.title.Render("✦ Keymap"), s.count.Render(count), contentWidth))
+	b.WriteString("\n")
+	b.WriteString(s.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+
+	keyWidth := 0
+	for _, row := range state.Rows {
+		keyWidth = max(keyWidth, lipgloss.Width(row.Key))
+	}
+	keyWidth = min(keyWidth, contentWidth/3)
+
+	if len(state.Rows) == 0 {
+		b.WriteString(s.muted.Width(contentWidth).Render("No dropped bindings."))
+		b.WriteString("\n")
+	} else {
+		start, end := rowWindow(len(state.Rows), state.Cursor, visibleRows(state.Height))
+		for i := start; i < end; i++ {
+			b.WriteString(renderRow(state.Rows[i], i == state.Cursor, keyWidth, contentWidth, s))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if state.Cursor >= 0 && state.Cursor < len(state.Rows) {
+		row := state.Rows[state.Cursor]
+		detail := row.Origin + " binding of " + row.Target
+		if row.Raw != "" {
+			detail += ", written " + row.Raw
+		}
+		b.WriteString(s.hint.Render(ansi.Truncate(detail, contentWidth, "…")))
+		b.WriteString("\n")
+		if row.Dropped != "" {
+			b.WriteString(s.err.Width(contentWidth).Render("Dropped: " + row.Key + " " + row.Dropped))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(s.muted.Width(contentWidth).Render(state.Footer))
+	return s.panel.Render(b.String())
+}
+
+func renderRow(row Row, active bool, keyWidth, width int, s keysStyles) string {
+	prefix := "  "
+	if active {
+		prefix = "✦ "
+	}
+	key := ansi.Truncate(row.Key, keyWidth, "…")
+	key += strings.Repeat(" ", keyWidth-lipgloss.Width(key))
+	right := row.Scope + " · " + row.Origin
+	if row.Dropped != "" {
+		right += " · dropped"
+	}
+	line := joinColumns(prefix+key+"  "+row.Target, right, width)
+
+	switch {
+	case active:
+		return s.rowActive.Width(width).Render(line)
+	case row.Dropped != "":
+		return s.dropped.Width(width).Render(line)
+	default:
+		return s.row.Width(width).Render(line)
+	}
+}
+
+// visibleRows returns how many bindings fit below the header and above the
+// detail lines.
+func visibleRows(height int) int {
+	rows := 16
+	if height > 0 {
+		rows = height - 10
+	}
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+func rowWindow(total, cursor, size int) (int, int) {
+	if total <= size {
+		return 0, total
+	}
+	start := cursor - size/2
+	if start < 0 {
+		start = 0
+	}
+	if start+size > total {
+		start = total - size
+	}
+	return start, start + size
+}
+
+func newKeysStyles() keysStyles {
+	return keysStyles{
+		title:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		count:     lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		muted:     lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		hint:      lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")),
+		row:       lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")),
+		rowActive: lipgloss.NewStyle().Foreground(lipgloss.Color("#2F1E0C")).Background(lipgloss.Color("#FFD9A0")).Bold(true),
+		dropped:   lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")).Strikethrough(true),
+		err:       lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 1),
+	}
+}
+
+func resolvePanelWidth(screenWidth int) int {
+	const (
+		defaultWidth = 100
+		minWidth     = 40
+	)
+	if screenWidth <= 0 {
+		return defaultWidth
+	}
+	max := screenWidth - 4
+	if max < minWidth {
+		return max
+	}
+	if defaultWidth > max {
+		return max
+	}
+	return defaultWidth
+}
+
+func joinColumns(left, right string, width int) string {
+	if width < 1 {
+		return left
+	}
+	if right == "" {
+		return ansi.Truncate(left, width, "…")
+	}
+	rightWidth := lipgloss.Width(right)
+	maxLeft := width - rightWidth - 1
+	if maxLeft < 1 {
+		return ansi.Truncate(left+" "+right, width, "…")
+	}
+	left = ansi.Truncate(left, maxLeft, "…")
+	space := width - lipgloss.Width(left) - rightWidth
+	if space < 1 {
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
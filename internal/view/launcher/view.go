@@ -11,10 +11,44 @@ import (
 
 type ViewState struct {
 	InputView string
-	Commands  []core.Command
-	Cursor    int
-	Width     int
-	Height    int
+	// Rows holds section headers followed by their commands.
+	Rows []Row
+	// Total is the number of matching commands across all sections.
+	Total  int
+	Cursor int
+	// Help, when set, replaces the list with the query syntax.
+	Help []HelpEntry
+	// Preview, when set, is shown below the list for the highlighted command.
+	Preview *Preview
+	// Footer lists the palette keys.
+	Footer string
+	Width  int
+	Height int
+}
+
+// Preview describes what the highlighted command would run.
+type Preview struct {
+	Fields []PreviewField
+	Script []string
+}
+
+type PreviewField struct {
+	Label string
+	Value string
+}
+
+type HelpEntry struct {
+	Syntax string
+	Text   string
+}
+
+// Row is a section header or a command.
+type Row struct {
+	Header    bool
+	Title     string
+	Count     int
+	Collapsed bool
+	Command   core.Command
 }
 
 type paletteStyles struct {
@@ -24,8 +58,14 @@ type paletteStyles struct {
 	searchBox    lipgloss.Style
 	row          lipgloss.Style
 	rowActive    lipgloss.Style
+	header       lipgloss.Style
+	headerActive lipgloss.Style
 	shortcutChip lipgloss.Style
 	activeChip   lipgloss.Style
+	previewLabel lipgloss.Style
+	previewCode  lipgloss.Style
+	dangerMedium lipgloss.Style
+	dangerHigh   lipgloss.Style
 	panel        lipgloss.Style
 }
 
@@ -46,15 +86,24 @@ func renderPanel(state ViewState) string {
 		contentWidth = 24
 	}
 
-	cursor := clampCursor(state.Cursor, len(state.Commands))
-	maxRows := resolveVisibleRows(state.Height, len(state.Commands))
-	visible, start, end := commandWindow(state.Commands, cursor, maxRows)
+	var preview []string
+	listHeight := state.Height
+	if state.Preview != nil {
+		preview = renderPreview(*state.Preview, contentWidth, resolvePreviewRows(state.Height), styles)
+		if listHeight > 0 {
+			listHeight -= len(preview) + 1
+		}
+	}
+
+	cursor := clampCursor(state.Cursor, len(state.Rows))
+	maxRows := resolveVisibleRows(listHeight, len(state.Rows))
+	visible, start, end := rowWindow(state.Rows, cursor, maxRows)
 
 	var b strings.Builder
 
 	header := joinColumns(
 		styles.title.Render("✦ Spellbook"),
-		styles.count.Render(strconv.Itoa(len(state.Commands))+" spells"),
+		styles.count.Render(strconv.Itoa(state.Total)+" spells"),
 		contentWidth,
 	)
 	b.WriteString(header)
@@ -68,26 +117,39 @@ func renderPanel(state ViewState) string {
 	b.WriteString(styles.muted.Render(strings.Repeat("·", contentWidth)))
 	b.WriteString("\n")
 
-	if len(state.Commands) == 0 {
-		b.WriteString(styles.muted.Width(contentWidth).Render("No matching commands"))
-	} else {
+	switch {
+	case len(state.Help) > 0:
+		for i, entry := range state.Help {
+			line := styles.header.Render(padRight(entry.Syntax, 12)) + " " + styles.row.Render(entry.Text)
+			b.WriteString(ansi.Truncate(line, contentWidth, "…"))
+			if i < len(state.Help)-1 {
+				b.WriteString("\n")
+			}
+		}
+	case len(state.Rows) == 0:
+		b.WriteString(styles.muted.Width(contentWidth).Render("No matching commands · ? for help"))
+	default:
 		if start > 0 {
 			topMore := styles.muted.Render("↑ " + strconv.Itoa(start) + " above")
 			b.WriteString(styles.muted.Width(contentWidth).Render(topMore))
 			b.WriteString("\n")
 		}
 
-		for i, cmd := range visible {
+		for i, row := range visible {
 			index := start + i
 			active := index == cursor
-			b.WriteString(renderCommandRow(cmd, active, contentWidth, styles))
-			if i < len(visible)-1 || end < len(state.Commands) {
+			if row.Header {
+				b.WriteString(renderHeaderRow(row, active, contentWidth, styles))
+			} else {
+				b.WriteString(renderCommandRow(row.Command, active, contentWidth, styles))
+			}
+			if i < len(visible)-1 || end < len(state.Rows) {
 				b.WriteString("\n")
 			}
 		}
 
-		if end < len(state.Commands) {
-			bottomMore := styles.muted.Render("↓ " + strconv.Itoa(len(state.Commands)-end) + " more")
+		if end < len(state.Rows) {
+			bottomMore := styles.muted.Render("↓ " + strconv.Itoa(len(state.Rows)-end) + " more")
 			if len(visible) > 0 {
 				b.WriteString("\n")
 			}
@@ -95,8 +157,17 @@ func renderPanel(state ViewState) string {
 		}
 	}
 
+	if state.Preview != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.muted.Render(strings.Repeat("·", contentWidth)))
+		for _, line := range preview {
+			b.WriteString("\n")
+			b.WriteString(line)
+		}
+	}
+
 	b.WriteString("\n")
-	b.WriteString(styles.muted.Width(contentWidth).Render("enter cast · esc close · ↑/↓ navigate"))
+	b.WriteString(styles.muted.Width(contentWidth).Render(state.Footer))
 
 	return styles.panel.Render(strings.TrimRight(b.String(), "\n"))
 }
@@ -117,6 +188,13 @@ func newPaletteStyles() paletteStyles {
 			Foreground(lipgloss.Color("#2F1E0C")).
 			Background(lipgloss.Color("#FFD9A0")).
 			Bold(true),
+		header: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF9F68")).
+			Bold(true),
+		headerActive: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#2F1E0C")).
+			Background(lipgloss.Color("#FFD9A0")).
+			Bold(true),
 		shortcutChip: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#D8DFEE")).
 			Background(lipgloss.Color("#252A35")).
@@ -126,6 +204,17 @@ func newPaletteStyles() paletteStyles {
 			Background(lipgloss.Color("#FFCF92")).
 			Bold(true).
 			Padding(0, 1),
+		previewLabel: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8A90A6")).
+			Width(9),
+		previewCode: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#9AA3B8")),
+		dangerMedium: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFB86C")).
+			Bold(true),
+		dangerHigh: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF6B6B")).
+			Bold(true),
 		panel: lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
 			BorderForeground(lipgloss.Color("#5C6475")).
@@ -174,6 +263,38 @@ func resolveVisibleRows(height, commandCount int) int {
 	return rows
 }
 
+// resolvePreviewRows gives the preview at most half of the available
+// height so the list stays usable.
+func resolvePreviewRows(height int) int {
+	if height <= 0 {
+		return 16
+	}
+	rows := (height - 9) / 2
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+func renderPreview(preview Preview, width, maxRows int, styles paletteStyles) []string {
+	lines := make([]string, 0, maxRows)
+	for _, field := range preview.Fields {
+		value := ansi.Truncate(field.Value, width-10, "…")
+		lines = append(lines, styles.previewLabel.Render(field.Label)+" "+styles.row.Render(value))
+	}
+	if len(preview.Script) > 0 {
+		lines = append(lines, "")
+		for _, line := range preview.Script {
+			lines = append(lines, styles.previewCode.Render(ansi.Truncate(line, width, "…")))
+		}
+	}
+	if len(lines) > maxRows {
+		more := len(lines) - maxRows + 1
+		lines = append(lines[:maxRows-1], styles.muted.Render("… "+strconv.Itoa(more)+" more lines"))
+	}
+	return lines
+}
+
 func clampCursor(cursor, size int) int {
 	if size == 0 {
 		return 0
@@ -187,26 +308,39 @@ func clampCursor(cursor, size int) int {
 	return cursor
 }
 
-func commandWindow(commands []core.Command, cursor, maxRows int) ([]core.Command, int, int) {
-	if len(commands) == 0 || maxRows <= 0 {
+func rowWindow(rows []Row, cursor, maxRows int) ([]Row, int, int) {
+	if len(rows) == 0 || maxRows <= 0 {
 		return nil, 0, 0
 	}
-	if len(commands) <= maxRows {
-		return commands, 0, len(commands)
+	if len(rows) <= maxRows {
+		return rows, 0, len(rows)
 	}
 	start := cursor - (maxRows / 2)
 	if start < 0 {
 		start = 0
 	}
 	end := start + maxRows
-	if end > len(commands) {
-		end = len(commands)
+	if end > len(rows) {
+		end = len(rows)
 		start = end - maxRows
 		if start < 0 {
 			start = 0
 		}
 	}
-	return commands[start:end], start, end
+	return rows[start:end], start, end
+}
+
+func renderHeaderRow(row Row, active bool, width int, styles paletteStyles) string {
+	marker := "▾ "
+	count := strconv.Itoa(row.Count)
+	if row.Collapsed {
+		marker = "▸ "
+		count += " hidden"
+	}
+	if active {
+		return styles.headerActive.Width(width).Render(joinColumns(marker+row.Title, count, width))
+	}
+	return joinColumns(styles.header.Render(marker+row.Title), styles.muted.Render(count), width)
 }
 
 func renderCommandRow(cmd core.Command, active bool, width int, styles paletteStyles) string {
@@ -214,8 +348,18 @@ func renderCommandRow(cmd core.Command, active bool, width int, styles paletteSt
 	if active {
 		left = "✦ " + cmd.Label
 	}
+	if cmd.Description != "" {
+		if active {
+			left += " · " + cmd.Description
+		} else {
+			left += styles.muted.Render(" · " + cmd.Description)
+		}
+	}
 
 	meta := make([]string, 0, 2)
+	if badge := dangerBadge(cmd.Danger, active, styles); badge != "" {
+		meta = append(meta, badge)
+	}
 	if cmd.Shortcut != "" {
 		if active {
 			meta = append(meta, styles.activeChip.Render(cmd.Shortcut))
@@ -232,6 +376,30 @@ func renderCommandRow(cmd core.Command, active bool, width int, styles paletteSt
 	return styles.row.Width(width).Render(row)
 }
 
+func dangerBadge(level core.DangerLevel, active bool, styles paletteStyles) string {
+	var text string
+	var style lipgloss.Style
+	switch level {
+	case core.DangerHigh:
+		text, style = "‼ danger", styles.dangerHigh
+	case core.DangerMedium:
+		text, style = "⚠", styles.dangerMedium
+	default:
+		return ""
+	}
+	if active {
+		return text
+	}
+	return style.Render(text)
+}
+
+func padRight(text string, width int) string {
+	if gap := width - lipgloss.Width(text); gap > 0 {
+		return text + strings.Repeat(" ", gap)
+	}
+	return text
+}
+
 func joinColumns(left, right string, width int) string {
 	if width < 1 {
 		return left
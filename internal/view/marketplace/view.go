@@ -18,6 +18,16 @@ type Entry struct {
 	InstalledProject bool
 	HasUpdateGlobal  bool
 	HasUpdateProject bool
+	// Suggestions lists the shortcuts each command suggests, by command ID.
+	Suggestions map[string][]Suggestion
+}
+
+// Suggestion is a shortcut a spellbook command suggests. Applied and Skipped
+// are only set once the spellbook is installed and loaded.
+type Suggestion struct {
+	Key     string
+	Applied bool
+	Skipped string // why config or another binding kept it from applying
 }
 
 // Installed returns true if installed in any scope.
@@ -39,10 +49,22 @@ type ViewState struct {
 	Installing     string
 	ConfirmInstall string // non-empty = showing scope prompt for this ID
 	HasProject     bool   // whether a project root exists
+	Keys           Keys
 	Width          int
 	Height         int
 }
 
+// Keys names the keys bound to the marketplace actions.
+type Keys struct {
+	Install   string
+	Uninstall string
+	Update    string
+	Back      string
+	Global    string
+	Project   string
+	Cancel    string
+}
+
 type styles struct {
 	title      lipgloss.Style
 	count      lipgloss.Style
@@ -305,12 +327,28 @@ func renderRightPanel(state ViewState, width int, s styles) string {
 			cmdLine := "  " + label + "  " + s.cmdID.Render(cmd.ID)
 			b.WriteString(ansi.Truncate(cmdLine, width, "…"))
 			b.WriteString("\n")
+			for _, suggestion := range entry.Suggestions[cmd.ID] {
+				b.WriteString(ansi.Truncate(renderSuggestion(suggestion, s), width, "…"))
+				b.WriteString("\n")
+			}
 		}
 	}
 
 	return strings.TrimRight(b.String(), "\n")
 }
 
+func renderSuggestion(suggestion Suggestion, s styles) string {
+	line := "    " + s.footerKey.Render(suggestion.Key) + "  "
+	switch {
+	case suggestion.Applied:
+		return line + s.scopeGlob.Render("applied")
+	case suggestion.Skipped != "":
+		return line + s.warn.Render("skipped: "+suggestion.Skipped)
+	default:
+		return line + s.muted.Render("suggested")
+	}
+}
+
 func renderFooter(state ViewState, width int, s styles) string {
 	var parts []string
 
@@ -319,20 +357,20 @@ func renderFooter(state ViewState, width int, s styles) string {
 		e := state.Entries[state.Cursor]
 		if state.Installing == "" {
 			if !e.Installed() {
-				parts = append(parts, s.footerKey.Render("i")+s.footerDesc.Render(" install"))
+				parts = append(parts, s.footerKey.Render(state.Keys.Install)+s.footerDesc.Render(" install"))
 			}
 			if e.Installed() {
-				parts = append(parts, s.footerKey.Render("u")+s.footerDesc.Render(" uninstall"))
+				parts = append(parts, s.footerKey.Render(state.Keys.Uninstall)+s.footerDesc.Render(" uninstall"))
 			}
 			if e.HasUpdate() {
-				parts = append(parts, s.footerKey.Render("U")+s.footerDesc.Render(" update"))
+				parts = append(parts, s.footerKey.Render(state.Keys.Update)+s.footerDesc.Render(" update"))
 			}
 		}
 	}
 
 	// Always-present navigation.
 	parts = append(parts,
-		s.footerKey.Render("esc")+s.footerDesc.Render(" back"),
+		s.footerKey.Render(state.Keys.Back)+s.footerDesc.Render(" back"),
 	)
 
 	footer := strings.Join(parts, s.footerDesc.Render(" · "))
@@ -431,9 +469,9 @@ func renderInstallModal(state ViewState, s styles) string {
 	globalIcon := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9F68")).Bold(true).Render("◉ ")
 	projectIcon := lipgloss.NewStyle().Foreground(lipgloss.Color("#C4B5FD")).Bold(true).Render("◎ ")
 
-	optG := s.footerKey.Render("g") + s.row.Render("  global")
-	optP := s.footerKey.Render("p") + s.row.Render("  project")
-	cancel := s.muted.Render("esc to cancel")
+	optG := s.footerKey.Render(state.Keys.Global) + s.row.Render("  global")
+	optP := s.footerKey.Render(state.Keys.Project) + s.row.Render("  project")
+	cancel := s.muted.Render(state.Keys.Cancel + " to cancel")
 
 	return strings.Join([]string{
 		title,
@@ -0,0 +1,190 @@
+package view
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type ViewState struct {
+	Title      string
+	Lines      []string
+	Cursor     int
+	Status     string
+	Failed     bool
+	Running    bool
+	Matches    map[int]bool
+	Query      string
+	SearchView string // non-empty while the search input is open
+	Footer     string
+	Width      int
+	Height     int
+}
+
+type outputStyles struct {
+	title     lipgloss.Style
+	count     lipgloss.Style
+	muted     lipgloss.Style
+	failed    lipgloss.Style
+	gutter    lipgloss.Style
+	match     lipgloss.Style
+	rowActive lipgloss.Style
+	panel     lipgloss.Style
+}
+
+// VisibleRows returns how many output lines fit for a screen height.
+func VisibleRows(height int) int {
+	rows := 20
+	if height > 0 {
+		rows = height - 7
+	}
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+func Render(state ViewState) string {
+	s := newOutputStyles()
+	contentWidth := resolvePanelWidth(state.Width) - 4
+	if contentWidth < 24 {
+		contentWidth = 24
+	}
+
+	statusStyle := s.count
+	if state.Failed {
+		statusStyle = s.failed
+	}
+	meta := strconv.Itoa(len(state.Lines)) + " lines · " + state.Status
+	if len(state.Matches) > 0 {
+		meta = strconv.Itoa(len(state.Matches)) + " matches · " + meta
+	}
+
+	var b strings.Builder
+	b.WriteString(joinColumns(s.title.Render("✦ "+state.Title), statusStyle.Render(meta), contentWidth))
+	b.WriteString("\n")
+	b.WriteString(s.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+
+	rows := VisibleRows(state.Height)
+	start, end := lineWindow(len(state.Lines), state.Cursor, rows)
+	gutterWidth := len(strconv.Itoa(len(state.Lines)))
+	if len(state.Lines) == 0 {
+		b.WriteString(s.muted.Render("no output"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		number := strconv.Itoa(i + 1)
+		gutter := strings.Repeat(" ", gutterWidth-len(number)) + number + " "
+		text := ansi.Truncate(state.Lines[i], contentWidth-gutterWidth-2, "…")
+		switch {
+		case i == state.Cursor:
+			line := "▸" + gutter + ansi.Strip(text)
+			b.WriteString(s.rowActive.Width(contentWidth).Render(line))
+		case state.Matches[i]:
+			b.WriteString(s.match.Render("•"+gutter) + text)
+		default:
+			b.WriteString(s.gutter.Render(" "+gutter) + text)
+		}
+		b.WriteString("\n")
+	}
+	for i := end - start; i < rows && len(state.Lines) > 0; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(s.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+	if state.SearchView != "" {
+		b.WriteString(state.SearchView)
+	} else {
+		footer := state.Footer
+		if state.Query != "" {
+			footer = "search: " + state.Query + " · " + footer
+		}
+		b.WriteString(s.muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+	}
+
+	panel := s.panel.Render(b.String())
+	if state.Width > 0 && state.Height > 0 {
+		panel = lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func lineWindow(total, cursor, rows int) (int, int) {
+	if total <= rows {
+		return 0, total
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor >= total {
+		cursor = total - 1
+	}
+	start := cursor - rows/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + rows
+	if end > total {
+		end = total
+		start = end - rows
+	}
+	return start, end
+}
+
+func newOutputStyles() outputStyles {
+	return outputStyles{
+		title:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		count:     lipgloss.NewStyle().Foreground(lipgloss.Color("#9AA3B8")),
+		muted:     lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		failed:    lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true),
+		gutter:    lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")),
+		match:     lipgloss.NewStyle().Foreground(lipgloss.Color("#FFCF92")).Bold(true),
+		rowActive: lipgloss.NewStyle().Foreground(lipgloss.Color("#2F1E0C")).Background(lipgloss.Color("#FFD9A0")),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 1),
+	}
+}
+
+func resolvePanelWidth(screenWidth int) int {
+	const (
+		defaultWidth = 120
+		minWidth     = 40
+	)
+	if screenWidth <= 0 {
+		return defaultWidth
+	}
+	max := screenWidth - 4
+	if max < minWidth {
+		return max
+	}
+	if defaultWidth > max {
+		return max
+	}
+	return defaultWidth
+}
+
+func joinColumns(left, right string, width int) string {
+	if width < 1 {
+		return left
+	}
+	if right == "" {
+		return ansi.Truncate(left, width, "…")
+	}
+	rightWidth := lipgloss.Width(right)
+	maxLeft := width - rightWidth - 1
+	if maxLeft < 1 {
+		return ansi.Truncate(left+" "+right, width, "…")
+	}
+	left = ansi.Truncate(left, maxLeft, "…")
+	space := width - lipgloss.Width(left) - rightWidth
+	if space < 1 {
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
@@ -0,0 +1,216 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Row is one command entry of the config file being edited.
+type Row struct {
+	ID       string
+	Label    string
+	Detail   string // what the command runs
+	Group    string
+	Shortcut string
+	Disabled bool
+}
+
+type ViewState struct {
+	Project    bool
+	HasProject bool
+	Path       string
+	Rows       []Row
+	Cursor     int
+	Deleting   bool
+	Err        string
+	Footer     string
+	Width      int
+	Height     int
+}
+
+type settingsStyles struct {
+	title     lipgloss.Style
+	tab       lipgloss.Style
+	tabActive lipgloss.Style
+	muted     lipgloss.Style
+	hint      lipgloss.Style
+	row       lipgloss.Style
+	rowActive lipgloss.Style
+	disabled  lipgloss.Style
+	err       lipgloss.Style
+	warn      lipgloss.Style
+	panel     lipgloss.Style
+}
+
+func Render(state ViewState) string {
+	panel := renderPanel(state)
+	if state.Width > 0 && state.Height > 0 {
+		panel = lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+func renderPanel(state ViewState) string {
+	s := newSettingsStyles()
+	contentWidth := resolvePanelWidth(state.Width) - 4
+	if contentWidth < 40 {
+		contentWidth = 40
+	}
+
+	global := s.tab.Render(" Global ")
+	project := s.tab.Render(" Project ")
+	if state.Project {
+		project = s.tabActive.Render(" Project ")
+	} else {
+		global = s.tabActive.Render(" Global ")
+	}
+
+	var b strings.Builder
+	b.WriteString(s.title.Render("✦ Commands & Shortcuts") + "  " + global + " " + project)
+	b.WriteString("\n")
+	path := state.Path
+	if state.Project && !state.HasProject {
+		path = "no project config yet · n creates .glyph/config.json here"
+	}
+	b.WriteString(s.hint.Render(ansi.Truncate(path, contentWidth, "…")))
+	b.WriteString("\n")
+	b.WriteString(s.muted.Render(strings.Repeat("·", contentWidth)))
+	b.WriteString("\n")
+
+	if len(state.Rows) == 0 {
+		b.WriteString(s.muted.Width(contentWidth).Render("No commands in this config. Press n to add one."))
+		b.WriteString("\n")
+	} else {
+		start, end := rowWindow(len(state.Rows), state.Cursor, visibleRows(state.Height))
+		for i := start; i < end; i++ {
+			b.WriteString(renderRow(state.Rows[i], i == state.Cursor, contentWidth, s))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	switch {
+	case state.Err != "":
+		b.WriteString(s.err.Width(contentWidth).Render(state.Err))
+	case state.Deleting && state.Cursor < len(state.Rows):
+		b.WriteString(s.warn.Render("Delete " + state.Rows[state.Cursor].ID + "? " + state.Footer))
+	default:
+		b.WriteString(s.muted.Width(contentWidth).Render(state.Footer))
+	}
+	return s.panel.Render(b.String())
+}
+
+func renderRow(row Row, active bool, width int, s settingsStyles) string {
+	prefix := "  "
+	if active {
+		prefix = "✦ "
+	}
+	label := row.Label
+	if label == "" {
+		label = row.ID
+	}
+	left := prefix + label + "  " + row.ID
+	if row.Disabled {
+		left += "  (disabled)"
+	}
+	right := row.Shortcut
+	if row.Group != "" {
+		right = strings.TrimSpace(right + "  [" + row.Group + "]")
+	}
+	line := joinColumns(left, right, width)
+	detail := ansi.Truncate("    "+row.Detail, width, "…")
+
+	switch {
+	case active:
+		return s.rowActive.Width(width).Render(line) + "\n" + s.hint.Render(detail)
+	case row.Disabled:
+		return s.disabled.Width(width).Render(line) + "\n" + s.hint.Render(detail)
+	default:
+		return s.row.Width(width).Render(line) + "\n" + s.hint.Render(detail)
+	}
+}
+
+// visibleRows returns how many entries fit; each entry takes two lines.
+func visibleRows(height int) int {
+	rows := 8
+	if height > 0 {
+		rows = (height - 10) / 2
+	}
+	if rows < 2 {
+		rows = 2
+	}
+	return rows
+}
+
+func rowWindow(total, cursor, size int) (int, int) {
+	if total <= size {
+		return 0, total
+	}
+	start := cursor - size/2
+	if start < 0 {
+		start = 0
+	}
+	if start+size > total {
+		start = total - size
+	}
+	return start, start + size
+}
+
+func newSettingsStyles() settingsStyles {
+	return settingsStyles{
+		title:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF9F68")),
+		tab:       lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		tabActive: lipgloss.NewStyle().Foreground(lipgloss.Color("#2F1E0C")).Background(lipgloss.Color("#FF9F68")).Bold(true),
+		muted:     lipgloss.NewStyle().Foreground(lipgloss.Color("#8A90A6")),
+		hint:      lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")),
+		row:       lipgloss.NewStyle().Foreground(lipgloss.Color("#E7EBF2")),
+		rowActive: lipgloss.NewStyle().Foreground(lipgloss.Color("#2F1E0C")).Background(lipgloss.Color("#FFD9A0")).Bold(true),
+		disabled:  lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6475")).Strikethrough(true),
+		err:       lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true),
+		warn:      lipgloss.NewStyle().Foreground(lipgloss.Color("#FFB86C")).Bold(true),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color("#5C6475")).
+			Padding(0, 1),
+	}
+}
+
+func resolvePanelWidth(screenWidth int) int {
+	const (
+		defaultWidth = 100
+		minWidth     = 40
+	)
+	if screenWidth <= 0 {
+		return defaultWidth
+	}
+	max := screenWidth - 4
+	if max < minWidth {
+		return max
+	}
+	if defaultWidth > max {
+		return max
+	}
+	return defaultWidth
+}
+
+func joinColumns(left, right string, width int) string {
+	if width < 1 {
+		return left
+	}
+	if right == "" {
+		return ansi.Truncate(left, width, "…")
+	}
+	rightWidth := lipgloss.Width(right)
+	maxLeft := width - rightWidth - 1
+	if maxLeft < 1 {
+		return ansi.Truncate(left+" "+right, width, "…")
+	}
+	left = ansi.Truncate(left, maxLeft, "…")
+	space := width - lipgloss.Width(left) - rightWidth
+	if space < 1 {
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}